@@ -22,15 +22,17 @@ var version = "dev"
 func printHelp() {
 	fmt.Println("gitx - A Git TUI Helper")
 	fmt.Println()
-	fmt.Println("Usage: gitx [options]")
+	fmt.Println("Usage: gitx [options] [path]")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -v, --version    Show version information")
 	fmt.Println("  -h, --help       Show this help message")
 	fmt.Println("  -i, --init       Initialize a new Git repository")
 	fmt.Println()
-	fmt.Println("Run 'gitx' inside a Git repository to start the TUI.")
-	fmt.Println("Or run 'gitx -i' to initialize a new Git repository in the current directory.")
+	fmt.Println("Run 'gitx' inside a Git repository to start the TUI, or 'gitx <path>' to open the")
+	fmt.Println("repository at path.")
+	fmt.Println("Outside a repository, gitx lets you choose one of the recently opened or configured ones.")
+	fmt.Println("Or run 'gitx -i [path]' to initialize a new Git repository in the current directory or path.")
 }
 
 func main() {
@@ -44,10 +46,11 @@ func main() {
 		}
 	}()
 
-	// Parse flags
+	// Parse flags and the path of the repository
 	shouldInit := false
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
+	repoPath := ""
+	for _, arg := range os.Args[1:] {
+		switch arg {
 		case "--version", "-v":
 			fmt.Printf("gitx version: %s\n", version)
 			return
@@ -56,11 +59,24 @@ func main() {
 			return
 		case "--init", "-i":
 			shouldInit = true
+		default:
+			if strings.HasPrefix(arg, "-") || repoPath != "" {
+				fmt.Fprintf(os.Stderr, "error: unexpected argument %q\n\n", arg)
+				printHelp()
+				os.Exit(1)
+			}
+			repoPath = arg
+		}
+	}
+	if repoPath != "" {
+		if info, err := os.Stat(repoPath); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "error: %s is not a directory\n", repoPath)
+			os.Exit(1)
 		}
 	}
 
-	// Ensure git repo exists (initialize if flag is set)
-	if err := ensureGitRepo(shouldInit); err != nil {
+	// Initialize a git repo if the flag is set
+	if err := ensureGitRepo(repoPath, shouldInit); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -68,7 +84,7 @@ func main() {
 	zone.NewGlobal()
 	defer zone.Close()
 
-	app := tui.NewApp()
+	app := tui.NewApp(repoPath)
 
 	if err := app.Run(); err != nil {
 		if !errors.Is(err, tea.ErrProgramKilled) {
@@ -78,18 +94,21 @@ func main() {
 	fmt.Println("Bye from gitx! :)")
 }
 
-func ensureGitRepo(shouldInit bool) error {
+// ensureGitRepo initializes a git repository in path, or the current directory
+// if path is empty, if shouldInit is set and it is not inside one yet.
+func ensureGitRepo(path string, shouldInit bool) error {
 	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	cmd.Dir = path
 	if err := cmd.Run(); err == nil {
 		return nil // Already inside a git repo
 	}
 
 	if !shouldInit {
-		return fmt.Errorf("error: not a git repository\nrun gitx -i/--init to initialize a new git repository and open gitx")
+		return nil // gitx starts with the repository switcher.
 	}
 
 	// Check if the directory is safe for git initialization
-	safe, err := checkInitSafety()
+	safe, err := checkInitSafety(path)
 	if err != nil {
 		return fmt.Errorf("safety check failed: %w", err)
 	}
@@ -98,24 +117,28 @@ func ensureGitRepo(shouldInit bool) error {
 	}
 
 	// Initialize a new git repository
-	g := &git.GitCommands{}
-	_, err = g.InitRepository(".")
+	g := git.NewGitCommands().WithRepoPath(path)
+	output, err := g.InitRepository(".")
 	if err != nil {
 		return fmt.Errorf("failed to initialize git repository: %w", err)
 	}
 
-	fmt.Println("Initialized new git repository in current directory")
+	fmt.Println(output)
 	return nil
 }
 
 // unsafeDirs is a list of system directories that should not be initialized as git repositories.
 var unsafeDirs = []string{"/", "/tmp"}
 
-// checkInitSafety verifies if initialization is safe in the current directory.
+// checkInitSafety verifies if initialization is safe in path, or the current
+// directory if path is empty.
 // If unsafe, it displays a warning and prompts for confirmation.
 // Returns true if safe or user confirmed, false otherwise.
-func checkInitSafety() (bool, error) {
-	absPath, err := filepath.Abs(".")
+func checkInitSafety(path string) (bool, error) {
+	if path == "" {
+		path = "."
+	}
+	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return false, fmt.Errorf("failed to resolve path: %w", err)
 	}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BisectTerm is the verdict given to a commit during a bisect.
+type BisectTerm string
+
+// Defines the verdicts that can be given to a commit during a bisect.
+const (
+	BisectGood BisectTerm = "good"
+	BisectBad  BisectTerm = "bad"
+	BisectSkip BisectTerm = "skip"
+)
+
+// BisectState describes the progress of a bisect. Commits are full hashes.
+type BisectState struct {
+	Bad       string
+	Good      []string
+	Skipped   []string
+	Current   string // The commit to test next, which is checked out.
+	Remaining int    // Revisions left to test after the current one.
+	Steps     int    // Estimated number of steps left.
+	FirstBad  string // The first bad commit, once it has been found.
+}
+
+// HasRange reports whether both a bad and a good commit have been marked, so
+// that the remaining range is known.
+func (b *BisectState) HasRange() bool {
+	return b.Bad != "" && len(b.Good) > 0
+}
+
+// BisectStart starts a bisect with the given commit as the first known bad one.
+func (g *GitCommands) BisectStart(bad string) (string, string, error) {
+	if bad == "" {
+		return "", "", fmt.Errorf("bad commit is required")
+	}
+
+	output, cmdStr, err := g.executeCommand("bisect", "start", bad)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("git bisect start failed: %w", err)
+	}
+	return output, cmdStr, nil
+}
+
+// BisectMark gives a verdict on a commit. If commit is empty, the commit that
+// is checked out is marked.
+func (g *GitCommands) BisectMark(term BisectTerm, commit string) (string, string, error) {
+	args := []string{"bisect", string(term)}
+	if commit != "" {
+		args = append(args, commit)
+	}
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("git bisect %s failed: %w", term, err)
+	}
+	return output, cmdStr, nil
+}
+
+// BisectRun runs a command on each remaining commit to mark it automatically,
+// as `git bisect run` does: exit code 0 is good, 125 is skip and anything else
+// is bad. The output of the command and of git is written to output as it is
+// produced.
+func (g *GitCommands) BisectRun(command string, output io.Writer) (string, string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", "", fmt.Errorf("command is required")
+	}
+
+	// Git quotes the arguments of the command, so run it through the shell to
+	// allow pipes and other shell syntax. Git for Windows ships with sh as well.
+	result, cmdStr, err := g.executeCommandWithOptions(execOptions{output: output}, "bisect", "run", "sh", "-c", command)
+	if err != nil {
+		return result, cmdStr, fmt.Errorf("git bisect run failed: %w", err)
+	}
+	return result, cmdStr, nil
+}
+
+// GetBisectState returns the marked commits and the remaining range of the
+// bisect in progress.
+func (g *GitCommands) GetBisectState() (*BisectState, error) {
+	refs, _, err := g.executeCommand("for-each-ref", "--format=%(refname) %(objectname)", "refs/bisect/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bisect refs: %w", err)
+	}
+
+	state := &BisectState{}
+	for _, line := range strings.Split(strings.TrimSpace(refs), "\n") {
+		ref, sha, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		switch name := strings.TrimPrefix(ref, "refs/bisect/"); {
+		case name == "bad":
+			state.Bad = sha
+		case strings.HasPrefix(name, "good-"):
+			state.Good = append(state.Good, sha)
+		case strings.HasPrefix(name, "skip-"):
+			state.Skipped = append(state.Skipped, sha)
+		}
+	}
+
+	if current, _, err := g.executeCommand("rev-parse", "HEAD"); err == nil {
+		state.Current = strings.TrimSpace(current)
+	}
+	if !state.HasRange() {
+		return state, nil
+	}
+
+	args := []string{"rev-list", "--bisect-vars", state.Bad, "--not"}
+	args = append(args, state.Good...)
+	vars, _, err := g.executeCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute bisect range: %w", err)
+	}
+	values := parseBisectVars(vars)
+	state.Remaining, _ = strconv.Atoi(values["bisect_nr"])
+	state.Steps, _ = strconv.Atoi(values["bisect_steps"])
+	if values["bisect_all"] == "1" {
+		state.FirstBad = state.Bad
+	}
+	return state, nil
+}
+
+// parseBisectVars parses the shell variable assignments printed by
+// `git rev-list --bisect-vars`, e.g. "bisect_nr=3".
+func parseBisectVars(output string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if ok {
+			values[key] = strings.Trim(value, "'")
+		}
+	}
+	return values
+}
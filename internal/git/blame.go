@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uncommittedSHA is the commit `git blame` reports for lines that have not
+// been committed yet.
+const uncommittedSHA = "0000000000000000000000000000000000000000"
+
+// BlameCommit holds the details of a commit that last modified some lines.
+type BlameCommit struct {
+	SHA          string
+	Author       string
+	Time         time.Time
+	Summary      string
+	Path         string // The path of the file in this commit.
+	Previous     string // The parent commit, empty for the commit that added the file.
+	PreviousPath string // The path of the file in the parent commit.
+}
+
+// IsUncommitted reports whether the lines have not been committed yet.
+func (c *BlameCommit) IsUncommitted() bool {
+	return c.SHA == uncommittedSHA
+}
+
+// BlameLine is a line of a file with the commit that last modified it.
+type BlameLine struct {
+	Commit  *BlameCommit
+	Line    int // The line number in the blamed revision of the file, starting at 1.
+	Content string
+}
+
+// BlameFile shows what revision and author last modified each line of a file
+// as of the given revision, or as it is in the work tree if revision is empty.
+func (g *GitCommands) BlameFile(filePath, revision string) ([]BlameLine, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+
+	args := []string{"blame", "--porcelain"}
+	if revision != "" {
+		args = append(args, revision)
+	}
+	args = append(args, "--", filePath)
+
+	output, _, err := g.executeCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame file %s: %w", filePath, err)
+	}
+	return ParseBlame(output), nil
+}
+
+// ParseBlame parses the output of `git blame --porcelain`. The details of a
+// commit are only printed for its first group of lines, so lines of the same
+// commit share a single BlameCommit.
+func ParseBlame(output string) []BlameLine {
+	var lines []BlameLine
+	commits := make(map[string]*BlameCommit)
+	var current *BlameLine
+
+	for _, line := range strings.Split(output, "\n") {
+		if current == nil {
+			// <sha> <orig_line> <final_line> [<num_lines>]
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			commit, ok := commits[fields[0]]
+			if !ok {
+				commit = &BlameCommit{SHA: fields[0]}
+				commits[fields[0]] = commit
+			}
+			current = &BlameLine{Commit: commit, Line: atoiOr(fields[2], 0)}
+			continue
+		}
+
+		if content, ok := strings.CutPrefix(line, "\t"); ok {
+			current.Content = content
+			lines = append(lines, *current)
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		commit := current.Commit
+		switch key {
+		case "author":
+			commit.Author = value
+		case "author-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				commit.Time = time.Unix(seconds, 0)
+			}
+		case "summary":
+			commit.Summary = value
+		case "filename":
+			commit.Path = value
+		case "previous":
+			// previous <sha> <path>
+			commit.Previous, commit.PreviousPath, _ = strings.Cut(value, " ")
+		}
+	}
+	return lines
+}
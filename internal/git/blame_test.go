@@ -0,0 +1,49 @@
+package git
+
+import "testing"
+
+const sampleBlame = "1111111111111111111111111111111111111111 1 1 2\n" +
+	"author Alice\n" +
+	"author-mail <alice@example.com>\n" +
+	"author-time 1700000000\n" +
+	"author-tz +0000\n" +
+	"summary Add file\n" +
+	"filename old.txt\n" +
+	"\tfirst line\n" +
+	"1111111111111111111111111111111111111111 2 2\n" +
+	"\tsecond line\n" +
+	"2222222222222222222222222222222222222222 3 3 1\n" +
+	"author Bob\n" +
+	"author-time 1700003600\n" +
+	"summary Change file\n" +
+	"previous 1111111111111111111111111111111111111111 old.txt\n" +
+	"filename new.txt\n" +
+	"\t\tindented line\n"
+
+func TestParseBlame(t *testing.T) {
+	lines := ParseBlame(sampleBlame)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %+v", len(lines), lines)
+	}
+
+	first, second, third := lines[0], lines[1], lines[2]
+	if first.Commit != second.Commit {
+		t.Error("expected lines of the same commit to share its details")
+	}
+	if first.Commit.Author != "Alice" || first.Commit.Summary != "Add file" || first.Commit.Time.Unix() != 1700000000 {
+		t.Errorf("unexpected commit details: %+v", first.Commit)
+	}
+	if first.Content != "first line" || second.Line != 2 || second.Content != "second line" {
+		t.Errorf("unexpected lines: %+v, %+v", first, second)
+	}
+	if first.Commit.Previous != "" {
+		t.Errorf("expected no previous commit, got %q", first.Commit.Previous)
+	}
+
+	if third.Content != "\tindented line" || third.Line != 3 {
+		t.Errorf("unexpected line: %+v", third)
+	}
+	if third.Commit.Previous != "1111111111111111111111111111111111111111" || third.Commit.PreviousPath != "old.txt" || third.Commit.Path != "new.txt" {
+		t.Errorf("unexpected previous commit: %+v", third.Commit)
+	}
+}
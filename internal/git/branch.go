@@ -10,12 +10,59 @@ type Branch struct {
 	Name       string
 	IsCurrent  bool
 	LastCommit string
+	Worktree   string // The linked worktree the branch is checked out in, if it is not the current one.
+	Tracking
 }
 
-// GetBranches fetches all local branches, their last commit time, and sorts them.
+// Tracking describes how a branch relates to its upstream branch.
+type Tracking struct {
+	Upstream string // The upstream branch, e.g. "origin/main", if one is configured.
+	Ahead    int    // The number of commits not pushed to the upstream.
+	Behind   int    // The number of upstream commits not merged into the branch.
+	Gone     bool   // Whether the upstream branch no longer exists.
+}
+
+// trackingFormat is the for-each-ref format of the fields parsed by parseTracking.
+const trackingFormat = "%(upstream:short)\t%(upstream:track)"
+
+// parseTracking parses the upstream branch and the output of
+// %(upstream:track), e.g. "[ahead 1, behind 2]" or "[gone]".
+func parseTracking(upstream, track string) Tracking {
+	tracking := Tracking{Upstream: upstream}
+	track = strings.Trim(track, "[]")
+	for _, part := range strings.Split(track, ", ") {
+		fields := strings.Fields(part)
+		switch {
+		case len(fields) == 1 && fields[0] == "gone":
+			tracking.Gone = true
+		case len(fields) == 2 && fields[0] == "ahead":
+			tracking.Ahead = atoiOr(fields[1], 0)
+		case len(fields) == 2 && fields[0] == "behind":
+			tracking.Behind = atoiOr(fields[1], 0)
+		}
+	}
+	return tracking
+}
+
+// getTracking returns how a local branch relates to its upstream branch.
+func (g *GitCommands) getTracking(branch string) (Tracking, error) {
+	output, _, err := g.executeCommand("for-each-ref", "--format="+trackingFormat, "refs/heads/"+branch)
+	if err != nil {
+		return Tracking{}, err
+	}
+	parts := strings.Split(strings.TrimRight(output, "\n"), "\t")
+	if len(parts) != 2 {
+		return Tracking{}, nil
+	}
+	return parseTracking(parts[0], parts[1]), nil
+}
+
+// GetBranches fetches all local branches, their last commit time and upstream
+// tracking, and sorts them.
 func (g *GitCommands) GetBranches() ([]*Branch, error) {
 	// This format gives us: <relative_commit_date> <tab> <branch_name> <tab> <is_current_indicator>
-	format := "%(committerdate:relative)\t%(refname:short)\t%(HEAD)"
+	// <tab> <upstream> <tab> <upstream_track> <tab> <worktree_path>
+	format := "%(committerdate:relative)\t%(refname:short)\t%(HEAD)\t" + trackingFormat + "\t%(worktreepath)"
 	args := []string{"for-each-ref", "--sort=-committerdate", "refs/heads/", fmt.Sprintf("--format=%s", format)}
 
 	output, _, err := g.executeCommand(args...)
@@ -37,12 +84,18 @@ func (g *GitCommands) GetBranches() ([]*Branch, error) {
 			continue
 		}
 
-		isCurrent := len(parts) == 3 && parts[2] == "*"
+		isCurrent := len(parts) >= 3 && parts[2] == "*"
 		branch := &Branch{
 			Name:       parts[1],
 			IsCurrent:  isCurrent,
 			LastCommit: formatRelativeDate(parts[0]),
 		}
+		if len(parts) >= 5 {
+			branch.Tracking = parseTracking(parts[3], parts[4])
+		}
+		if len(parts) == 6 && !isCurrent {
+			branch.Worktree = parts[5]
+		}
 
 		if isCurrent {
 			currentBranch = branch
@@ -164,3 +217,34 @@ func (g *GitCommands) RenameBranch(oldName, newName string) (string, string, err
 
 	return string(output), cmdStr, nil
 }
+
+// SetUpstream sets the upstream of a local branch to a remote-tracking branch,
+// e.g. "origin/main".
+func (g *GitCommands) SetUpstream(branchName, upstream string) (string, string, error) {
+	if branchName == "" || upstream == "" {
+		return "", "", fmt.Errorf("both branch name and upstream are required")
+	}
+	args := []string{"branch", "--set-upstream-to=" + upstream, branchName}
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return string(output), cmdStr, err
+	}
+
+	return string(output), cmdStr, nil
+}
+
+// UnsetUpstream removes the upstream of a local branch.
+func (g *GitCommands) UnsetUpstream(branchName string) (string, string, error) {
+	if branchName == "" {
+		return "", "", fmt.Errorf("branch name is required")
+	}
+	args := []string{"branch", "--unset-upstream", branchName}
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return string(output), cmdStr, err
+	}
+
+	return string(output), cmdStr, nil
+}
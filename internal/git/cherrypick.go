@@ -0,0 +1,24 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CherryPick applies the changes of the given commits on top of HEAD, in the
+// order they are given. If a commit conflicts, the cherry-pick stops and can be
+// continued or aborted with ContinueOperation or AbortOperation.
+func (g *GitCommands) CherryPick(commits []string) (string, string, error) {
+	if len(commits) == 0 {
+		return "", "", fmt.Errorf("at least one commit is required")
+	}
+
+	args := append([]string{"cherry-pick"}, commits...)
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("git cherry-pick failed for commits %s: %w", strings.Join(commits, ", "), err)
+	}
+
+	return output, cmdStr, nil
+}
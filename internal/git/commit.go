@@ -52,3 +52,23 @@ func (g *GitCommands) ShowCommit(commitHash string) (string, error) {
 
 	return string(output), nil
 }
+
+// ShowCommitFile shows the changes a commit made to a single file.
+func (g *GitCommands) ShowCommitFile(commitHash, path string) (string, error) {
+	if commitHash == "" || path == "" {
+		return "", fmt.Errorf("both commit and file path are required")
+	}
+	args := []string{"show", "--color=always", commitHash, "--", path}
+
+	output, _, err := g.executeCommand(args...)
+	if err != nil {
+		return string(output), fmt.Errorf(
+			"failed to show %s in commit %s: %w",
+			path,
+			commitHash,
+			err,
+		)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,224 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Conflict markers written by git into conflicted files.
+const (
+	conflictMarkerOurs   = "<<<<<<<"
+	conflictMarkerBase   = "|||||||"
+	conflictMarkerSep    = "======="
+	conflictMarkerTheirs = ">>>>>>>"
+)
+
+// ConflictResolution is the choice made for a single conflict block.
+type ConflictResolution int
+
+// Defines the ways a conflict block can be resolved.
+const (
+	ResolutionNone ConflictResolution = iota
+	ResolutionOurs
+	ResolutionTheirs
+	ResolutionBoth // Ours followed by theirs.
+)
+
+// ConflictSide selects one side of a conflicted file as a whole.
+type ConflictSide string
+
+// Defines the sides that can be checked out for a conflicted file.
+const (
+	SideOurs   ConflictSide = "ours"
+	SideTheirs ConflictSide = "theirs"
+)
+
+// ConflictBlock is a single region of a file delimited by conflict markers.
+type ConflictBlock struct {
+	OursLabel   string   // The text following "<<<<<<<", e.g. "HEAD".
+	BaseLabel   string   // The text following "|||||||", only set for the diff3 style.
+	TheirsLabel string   // The text following ">>>>>>>", e.g. the merged branch.
+	Ours        []string // The lines of our side.
+	Base        []string // The lines of the merge base, only set for the diff3 style.
+	Theirs      []string // The lines of their side.
+	Resolution  ConflictResolution
+}
+
+// Lines returns the lines that replace the block for its resolution. An
+// unresolved block keeps its conflict markers.
+func (b *ConflictBlock) Lines() []string {
+	switch b.Resolution {
+	case ResolutionOurs:
+		return b.Ours
+	case ResolutionTheirs:
+		return b.Theirs
+	case ResolutionBoth:
+		return append(append([]string{}, b.Ours...), b.Theirs...)
+	}
+
+	lines := []string{strings.TrimSpace(conflictMarkerOurs + " " + b.OursLabel)}
+	lines = append(lines, b.Ours...)
+	if b.Base != nil {
+		lines = append(lines, strings.TrimSpace(conflictMarkerBase+" "+b.BaseLabel))
+		lines = append(lines, b.Base...)
+	}
+	lines = append(lines, conflictMarkerSep)
+	lines = append(lines, b.Theirs...)
+	return append(lines, strings.TrimSpace(conflictMarkerTheirs+" "+b.TheirsLabel))
+}
+
+// ConflictChunk is a part of a conflicted file: either lines without conflicts
+// or a single conflict block.
+type ConflictChunk struct {
+	Lines    []string       // The lines of the chunk, if it is not a conflict.
+	Conflict *ConflictBlock // The conflict, or nil.
+}
+
+// ConflictedFile is the content of a conflicted file split into chunks.
+type ConflictedFile struct {
+	Path   string
+	Chunks []ConflictChunk
+}
+
+// Blocks returns the conflict blocks of the file in order.
+func (f *ConflictedFile) Blocks() []*ConflictBlock {
+	var blocks []*ConflictBlock
+	for _, chunk := range f.Chunks {
+		if chunk.Conflict != nil {
+			blocks = append(blocks, chunk.Conflict)
+		}
+	}
+	return blocks
+}
+
+// IsResolved reports whether a resolution has been chosen for every block.
+func (f *ConflictedFile) IsResolved() bool {
+	for _, block := range f.Blocks() {
+		if block.Resolution == ResolutionNone {
+			return false
+		}
+	}
+	return true
+}
+
+// Content returns the file content with every block replaced by its resolution.
+func (f *ConflictedFile) Content() string {
+	var lines []string
+	for _, chunk := range f.Chunks {
+		if chunk.Conflict != nil {
+			lines = append(lines, chunk.Conflict.Lines()...)
+		} else {
+			lines = append(lines, chunk.Lines...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseConflicts splits the content of a conflicted file into chunks. Content
+// without conflict markers results in a single chunk without conflicts.
+func ParseConflicts(path, content string) *ConflictedFile {
+	file := &ConflictedFile{Path: path}
+	var plain, blockLines []string
+	var block *ConflictBlock
+	section := &plain // The slice the next line is appended to.
+
+	for _, line := range strings.Split(content, "\n") {
+		if block != nil {
+			blockLines = append(blockLines, line)
+		}
+		switch {
+		case block == nil && strings.HasPrefix(line, conflictMarkerOurs):
+			if plain != nil {
+				file.Chunks = append(file.Chunks, ConflictChunk{Lines: plain})
+				plain = nil
+			}
+			block = &ConflictBlock{OursLabel: markerLabel(line), Ours: []string{}, Theirs: []string{}}
+			blockLines = []string{line}
+			section = &block.Ours
+		case block != nil && strings.HasPrefix(line, conflictMarkerBase):
+			block.BaseLabel = markerLabel(line)
+			block.Base = []string{}
+			section = &block.Base
+		case block != nil && strings.TrimSuffix(line, "\r") == conflictMarkerSep:
+			section = &block.Theirs
+		case block != nil && strings.HasPrefix(line, conflictMarkerTheirs):
+			block.TheirsLabel = markerLabel(line)
+			file.Chunks = append(file.Chunks, ConflictChunk{Conflict: block})
+			block = nil
+			section = &plain
+		default:
+			*section = append(*section, line)
+		}
+	}
+
+	if block != nil {
+		// An unterminated block is not a conflict, so keep its lines as they are.
+		plain = append(plain, blockLines...)
+	}
+	if plain != nil {
+		file.Chunks = append(file.Chunks, ConflictChunk{Lines: plain})
+	}
+	return file
+}
+
+// markerLabel returns the text following a conflict marker.
+func markerLabel(line string) string {
+	return strings.TrimSpace(line[len(conflictMarkerOurs):])
+}
+
+// GetConflictedFile reads a conflicted file from the working tree. The path is
+// relative to the root of the repository, as reported by GetRepoStatus.
+func (g *GitCommands) GetConflictedFile(path string) (*ConflictedFile, error) {
+	fullPath, err := g.repoRootPath(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conflicted file %s: %w", path, err)
+	}
+	return ParseConflicts(path, string(content)), nil
+}
+
+// ResolveConflictedFile writes the resolved content of a conflicted file to the
+// working tree and stages it, which marks the conflict as resolved.
+func (g *GitCommands) ResolveConflictedFile(file *ConflictedFile) (string, string, error) {
+	if !file.IsResolved() {
+		return "", "", fmt.Errorf("%s still has unresolved conflicts", file.Path)
+	}
+	fullPath, err := g.repoRootPath(file.Path)
+	if err != nil {
+		return "", "", err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read conflicted file %s: %w", file.Path, err)
+	}
+	if err := os.WriteFile(fullPath, []byte(file.Content()), info.Mode().Perm()); err != nil {
+		return "", "", fmt.Errorf("failed to write resolved file %s: %w", file.Path, err)
+	}
+	return g.AddFiles([]string{fullPath})
+}
+
+// CheckoutConflictSide resolves a conflicted file by taking one side as a
+// whole, as `git checkout --ours` or `--theirs` does, and stages the result.
+func (g *GitCommands) CheckoutConflictSide(path string, side ConflictSide) (string, string, error) {
+	if path == "" {
+		return "", "", fmt.Errorf("file path is required")
+	}
+	fullPath, err := g.repoRootPath(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	output, cmdStr, err := g.executeCommand("checkout", "--"+string(side), "--", fullPath)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("git checkout --%s failed for %s: %w", side, path, err)
+	}
+	if _, _, err := g.AddFiles([]string{fullPath}); err != nil {
+		return output, cmdStr, err
+	}
+	return output, cmdStr, nil
+}
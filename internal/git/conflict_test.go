@@ -0,0 +1,43 @@
+package git
+
+import "testing"
+
+func TestParseConflicts(t *testing.T) {
+	content := "top\n" +
+		"<<<<<<< HEAD\nours 1\nours 2\n=======\ntheirs\n>>>>>>> feature\n" +
+		"middle\n" +
+		"<<<<<<< HEAD\nours\n||||||| base\nbase\n=======\n>>>>>>> feature\n" +
+		"bottom\n"
+
+	file := ParseConflicts("file.txt", content)
+	blocks := file.Blocks()
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 conflict blocks, got %d: %+v", len(blocks), file.Chunks)
+	}
+	if blocks[0].OursLabel != "HEAD" || blocks[0].TheirsLabel != "feature" || len(blocks[0].Ours) != 2 || len(blocks[0].Theirs) != 1 {
+		t.Errorf("unexpected first block: %+v", blocks[0])
+	}
+	if len(blocks[1].Base) != 1 || len(blocks[1].Theirs) != 0 {
+		t.Errorf("unexpected diff3 block: %+v", blocks[1])
+	}
+
+	// Unresolved blocks keep their markers.
+	if file.IsResolved() || file.Content() != content {
+		t.Errorf("expected unresolved content to round-trip, got:\n%s", file.Content())
+	}
+
+	blocks[0].Resolution = ResolutionBoth
+	blocks[1].Resolution = ResolutionTheirs
+	want := "top\nours 1\nours 2\ntheirs\nmiddle\nbottom\n"
+	if !file.IsResolved() || file.Content() != want {
+		t.Errorf("got resolved content %q, want %q", file.Content(), want)
+	}
+}
+
+func TestParseConflicts_Unterminated(t *testing.T) {
+	content := "a\n<<<<<<< HEAD\nb\n"
+	file := ParseConflicts("file.txt", content)
+	if len(file.Blocks()) != 0 || file.Content() != content {
+		t.Errorf("expected no conflicts and unchanged content, got %+v", file.Chunks)
+	}
+}
@@ -11,6 +11,9 @@ type DiffOptions struct {
 	Cached  bool
 	Stat    bool
 	Color   bool
+	// Submodule sets how changes to submodules are shown: "short", "log" or
+	// "diff". Git's default is used if it is empty.
+	Submodule string
 }
 
 // ShowDiff shows changes between commits, commit and working tree, etc.
@@ -26,6 +29,9 @@ func (g *GitCommands) ShowDiff(options DiffOptions) (string, error) {
 	if options.Stat {
 		args = append(args, "--stat")
 	}
+	if options.Submodule != "" {
+		args = append(args, "--submodule="+options.Submodule)
+	}
 
 	if options.Commit1 != "" || options.Commit2 != "" {
 		args = append(args, "--")
@@ -48,3 +54,61 @@ func (g *GitCommands) ShowDiff(options DiffOptions) (string, error) {
 
 	return string(output), nil
 }
+
+// GetFileDiff returns the parsed diff of a single file, either between the
+// index and the working tree or, if cached is set, between HEAD and the index.
+// The returned FileDiff has no hunks if the file has no changes on that side.
+func (g *GitCommands) GetFileDiff(path string, cached bool) (*FileDiff, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	if cached {
+		args = append(args, "--cached")
+	}
+	args = append(args, "--", path)
+
+	output, _, err := g.executeCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff file %s: %w", path, err)
+	}
+
+	diffs := ParseDiff(output)
+	if len(diffs) == 0 {
+		return &FileDiff{}, nil
+	}
+	return &diffs[0], nil
+}
+
+// ApplyOptions specifies the options for the git apply command.
+type ApplyOptions struct {
+	Cached  bool
+	Reverse bool
+}
+
+// ApplyPatch applies a patch, read from standard input, to the working tree
+// or, if Cached is set, to the index.
+func (g *GitCommands) ApplyPatch(patch string, options ApplyOptions) (string, string, error) {
+	if patch == "" {
+		return "", "", fmt.Errorf("patch is required")
+	}
+
+	args := []string{"apply"}
+
+	if options.Cached {
+		args = append(args, "--cached")
+	}
+	if options.Reverse {
+		args = append(args, "--reverse")
+	}
+
+	args = append(args, "-")
+
+	output, cmdStr, err := g.executeCommandWithOptions(execOptions{input: patch}, args...)
+	if err != nil {
+		return string(output), cmdStr, fmt.Errorf("git apply failed: %w", err)
+	}
+
+	return string(output), cmdStr, nil
+}
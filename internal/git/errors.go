@@ -0,0 +1,112 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorKind classifies why a git command failed, so callers can react to it
+// without parsing the output of git.
+type ErrorKind int
+
+const (
+	ErrorUnknown ErrorKind = iota
+	ErrorNotARepository
+	ErrorConflict
+	ErrorLockHeld
+	ErrorAuthFailed
+	ErrorNothingToCommit
+	ErrorNoStash
+)
+
+// String returns a short description of the kind of error.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorNotARepository:
+		return "not a repository"
+	case ErrorConflict:
+		return "conflict"
+	case ErrorLockHeld:
+		return "lock held"
+	case ErrorAuthFailed:
+		return "authentication failed"
+	case ErrorNothingToCommit:
+		return "nothing to commit"
+	case ErrorNoStash:
+		return "no stash"
+	}
+	return "unknown"
+}
+
+// errorPatterns maps messages git prints to the kind of error they report.
+// The patterns are matched against the lower-cased output in order.
+var errorPatterns = []struct {
+	pattern string
+	kind    ErrorKind
+}{
+	{"not a git repository", ErrorNotARepository},
+	{"index.lock': file exists", ErrorLockHeld},
+	{"another git process seems to be running", ErrorLockHeld},
+	{"authentication failed", ErrorAuthFailed},
+	{"could not read username", ErrorAuthFailed},
+	{"could not read password", ErrorAuthFailed},
+	{"terminal prompts disabled", ErrorAuthFailed},
+	{"permission denied (publickey", ErrorAuthFailed},
+	{"conflict (", ErrorConflict},
+	{"automatic merge failed", ErrorConflict},
+	{"could not apply", ErrorConflict},
+	{"you need to resolve your current index first", ErrorConflict},
+	{"nothing to commit", ErrorNothingToCommit},
+	{"nothing added to commit", ErrorNothingToCommit},
+	{"no changes added to commit", ErrorNothingToCommit},
+	{"no stash entries found", ErrorNoStash},
+	{"no stash found", ErrorNoStash},
+}
+
+// classifyError returns the kind of error reported by the output of git.
+func classifyError(stdout, stderr string) ErrorKind {
+	output := strings.ToLower(stderr + "\n" + stdout)
+	for _, p := range errorPatterns {
+		if strings.Contains(output, p.pattern) {
+			return p.kind
+		}
+	}
+	return ErrorUnknown
+}
+
+// GitError is returned when a git command exits with an error.
+type GitError struct {
+	Args     []string // The arguments passed to git.
+	ExitCode int      // The exit code of git, or 0 if it could not be run.
+	Stdout   string
+	Stderr   string
+	Kind     ErrorKind
+	Err      error // The error from running the process.
+}
+
+// Error returns the message git printed for the error.
+func (e *GitError) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = strings.TrimSpace(e.Stdout)
+	}
+	msg = strings.TrimPrefix(msg, "fatal: ")
+	msg = strings.TrimPrefix(msg, "error: ")
+	return fmt.Sprintf("[ERROR - %d] %s", e.ExitCode, msg)
+}
+
+// Unwrap returns the error from running the process.
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKindOf returns the kind of the GitError in the chain of err, or
+// ErrorUnknown if there is none.
+func ErrorKindOf(err error) ErrorKind {
+	var gitErr *GitError
+	if errors.As(err, &gitErr) {
+		return gitErr.Kind
+	}
+	return ErrorUnknown
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		stdout, stderr string
+		want           ErrorKind
+	}{
+		{"", "fatal: not a git repository (or any of the parent directories): .git", ErrorNotARepository},
+		{"CONFLICT (content): Merge conflict in a.txt\nAutomatic merge failed", "", ErrorConflict},
+		{"", "error: could not apply 1234567... change", ErrorConflict},
+		{"", "fatal: Unable to create '/repo/.git/index.lock': File exists.", ErrorLockHeld},
+		{"", "fatal: could not read Username for 'https://example.com': terminal prompts disabled", ErrorAuthFailed},
+		{"", "git@example.com: Permission denied (publickey).", ErrorAuthFailed},
+		{"On branch master\nnothing to commit, working tree clean", "", ErrorNothingToCommit},
+		{"", "No stash entries found.", ErrorNoStash},
+		{"", "fatal: bad revision 'nope'", ErrorUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.stdout, tt.stderr); got != tt.want {
+			t.Errorf("classifyError(%q, %q) = %v, want %v", tt.stdout, tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestGitCommands_Errors(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+
+	// Committing without changes reports it on standard output.
+	_, _, err := g.Commit(CommitOptions{Message: "Nothing"})
+	var gitErr *GitError
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("expected a GitError, got %v", err)
+	}
+	if gitErr.Kind != ErrorNothingToCommit || gitErr.ExitCode != 1 {
+		t.Errorf("unexpected error: %+v", gitErr)
+	}
+	if gitErr.Args[0] != "commit" || !strings.Contains(gitErr.Stdout, "nothing to commit") {
+		t.Errorf("unexpected args or output: %+v", gitErr)
+	}
+
+	// Errors keep their kind when wrapped.
+	if kind := ErrorKindOf(fmt.Errorf("wrapped: %w", err)); kind != ErrorNothingToCommit {
+		t.Errorf("expected the kind of a wrapped error, got %v", kind)
+	}
+	if kind := ErrorKindOf(errors.New("other")); kind != ErrorUnknown {
+		t.Errorf("expected an unknown kind, got %v", kind)
+	}
+
+	// Popping without stashes is not an error.
+	output, _, err := g.Stash(StashOptions{Pop: true})
+	if err != nil || output != "No stashes found." {
+		t.Errorf("expected no stashes, got %q, %v", output, err)
+	}
+
+	// A held index lock is reported on standard error.
+	lock := filepath.Join(".git", "index.lock")
+	if err := os.WriteFile(lock, nil, 0644); err != nil {
+		t.Fatalf("failed to create lock: %v", err)
+	}
+	if err := os.WriteFile("locked.txt", []byte("content"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	_, _, err = g.AddFiles([]string{"locked.txt"})
+	if !errors.As(err, &gitErr) || gitErr.Kind != ErrorLockHeld || gitErr.Stdout != "" || gitErr.Stderr == "" {
+		t.Errorf("expected a lock error on standard error, got %#v", err)
+	}
+	if err := os.Remove(lock); err != nil {
+		t.Fatalf("failed to remove lock: %v", err)
+	}
+}
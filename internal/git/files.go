@@ -10,26 +10,7 @@ func (g *GitCommands) ListFiles() (string, error) {
 
 	output, _, err := g.executeCommand(args...)
 	if err != nil {
-		return string(output), fmt.Errorf("failed to list files: %v", err)
-	}
-
-	return string(output), nil
-}
-
-// BlameFile shows what revision and author last modified each line of a file.
-func (g *GitCommands) BlameFile(filePath string) (string, error) {
-	if filePath == "" {
-		return "", fmt.Errorf("file path is required")
-	}
-
-	args := []string{"blame", filePath}
-	output, _, err := g.executeCommand(args...)
-	if err != nil {
-		return string(output), fmt.Errorf(
-			"failed to blame file %s: %w",
-			filePath,
-			err,
-		)
+		return string(output), fmt.Errorf("failed to list files: %w", err)
 	}
 
 	return string(output), nil
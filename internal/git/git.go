@@ -1,54 +1,287 @@
 package git
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
-// ExecCommand is a variable that holds the exec.Command function
+// ExecCommand is a variable that holds the exec.CommandContext function
 // This allows it to be mocked in tests
-var ExecCommand = exec.Command
+var ExecCommand = exec.CommandContext
+
+// commandWaitDelay is how long a cancelled git process is waited for before it
+// is killed, e.g. while it removes its lock files or a credential helper it
+// started still holds its output open.
+const commandWaitDelay = time.Second
+
+// Timeouts limits how long git processes may run. A zero duration means no
+// limit.
+type Timeouts struct {
+	Command     time.Duration // The limit for most commands.
+	LongRunning time.Duration // The limit for commands that talk to a remote or stream their output, e.g. push or bisect run.
+}
 
 // GitCommands provides an interface to execute Git commands.
-type GitCommands struct{}
+type GitCommands struct {
+	ctx      context.Context // Cancels the git processes; nil means context.Background().
+	timeouts Timeouts
+	repoPath string   // The work tree the commands run in, passed to git as -C; empty for the current directory.
+	env      []string // Added to the environment of every git process, as "KEY=value".
+}
 
-// NewGitCommands creates a new instance of GitCommands.
+// NewGitCommands creates a new instance of GitCommands, which runs the
+// commands in the current directory.
 func NewGitCommands() *GitCommands {
 	return &GitCommands{}
 }
 
+// WithRepoPath returns a copy of the commands which run in the work tree at
+// path rather than in the current directory.
+func (g *GitCommands) WithRepoPath(path string) *GitCommands {
+	commands := *g
+	commands.repoPath = path
+	return &commands
+}
+
+// RepoPath returns the work tree the commands run in, or an empty string for
+// the current directory.
+func (g *GitCommands) RepoPath() string {
+	return g.repoPath
+}
+
+// WithEnv returns a copy of the commands which add the given variables, as
+// "KEY=value", to the environment of every git process.
+func (g *GitCommands) WithEnv(env ...string) *GitCommands {
+	commands := *g
+	commands.env = append(slices.Clone(g.env), env...)
+	return &commands
+}
+
+// WithContext returns a copy of the commands whose git processes are killed
+// when ctx is done. The methods of the copy return an error wrapping
+// ctx.Err() in that case.
+func (g *GitCommands) WithContext(ctx context.Context) *GitCommands {
+	commands := *g
+	commands.ctx = ctx
+	return &commands
+}
+
+// WithTimeouts returns a copy of the commands whose git processes are killed
+// once they run longer than the given timeouts.
+func (g *GitCommands) WithTimeouts(timeouts Timeouts) *GitCommands {
+	commands := *g
+	commands.timeouts = timeouts
+	return &commands
+}
+
+// Context returns the context the git processes run in.
+func (g *GitCommands) Context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
+// commandContext returns the context for a single git process, which applies
+// the timeout for its kind.
+func (g *GitCommands) commandContext(longRunning bool) (context.Context, time.Duration, context.CancelFunc) {
+	timeout := g.timeouts.Command
+	if longRunning {
+		timeout = g.timeouts.LongRunning
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(g.Context())
+		return ctx, 0, cancel
+	}
+	ctx, cancel := context.WithTimeout(g.Context(), timeout)
+	return ctx, timeout, cancel
+}
+
+// command returns the git process for the given arguments, which runs in the
+// work tree of the commands with their environment and the extra env.
+func (g *GitCommands) command(ctx context.Context, env []string, args []string) *exec.Cmd {
+	if g.repoPath != "" {
+		args = append([]string{"-C", g.repoPath}, args...)
+	}
+	cmd := ExecCommand(ctx, "git", args...)
+	// A cancelled git process is interrupted rather than killed, so it can
+	// remove its lock files, e.g. index.lock, before it exits.
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill() // Interrupting is not supported on Windows.
+		}
+		return nil
+	}
+	cmd.WaitDelay = commandWaitDelay
+	if env = append(slices.Clone(g.env), env...); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
 // executeCommand centralizes the execution of all git commands and serves
 // as a single point for logging. It takes a list of flags passed to the git
 // command as arguments and returns 1. standard output, 2. the command string
 // and 3. standard error
 func (g *GitCommands) executeCommand(args ...string) (string, string, error) {
+	return g.executeCommandWithOptions(execOptions{}, args...)
+}
+
+// execOptions holds additional settings for a single git invocation.
+type execOptions struct {
+	input string   // Fed to the standard input of the git process, e.g. a patch for `git apply -`.
+	env   []string // Added to the environment of the git process, as "KEY=value".
+	// output receives the combined output of the git process while it runs,
+	// e.g. to show progress. The output is returned as usual as well.
+	output io.Writer
+	remote bool // Whether the git process talks to a remote.
+	// includeStderr appends the standard error of the git process to the
+	// returned output, for commands that report their result there, e.g. rebase.
+	includeStderr bool
+}
+
+// executeCommandWithOptions behaves like executeCommand, but applies the given
+// execOptions to the git process.
+func (g *GitCommands) executeCommandWithOptions(options execOptions, args ...string) (string, string, error) {
 	cmdStr := "git " + strings.Join(args, " ")
 	log.Printf("Executing command: %s", cmdStr)
 
-	cmd := ExecCommand("git", args...)
-	output, err := cmd.CombinedOutput()
+	ctx, timeout, cancel := g.commandContext(options.remote || options.output != nil)
+	defer cancel()
+
+	cmd := g.command(ctx, options.env, args)
+	if options.input != "" {
+		cmd.Stdin = strings.NewReader(options.input)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if options.output != nil {
+		// The streams are copied from goroutines of their own, so the writes
+		// to the shared output are serialized.
+		output := &syncWriter{w: options.output}
+		cmd.Stdout = io.MultiWriter(&stdout, output)
+		cmd.Stderr = io.MultiWriter(&stderr, output)
+	}
+	err := cmd.Run()
 
 	if err != nil {
-		log.Printf("Error: %v, Output: %s", err, string(output))
+		if ctxErr := contextError(ctx, cmdStr, timeout); ctxErr != nil {
+			return "", cmdStr, ctxErr
+		}
+		return "", cmdStr, commandError(args, err, stdout.String(), stderr.String())
+	}
+
+	if options.includeStderr {
+		return stdout.String() + stderr.String(), cmdStr, nil
+	}
+	return stdout.String(), cmdStr, nil
+}
+
+// syncWriter is an io.Writer that passes the writes of several goroutines to
+// w one at a time.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write implements io.Writer.
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// maxStreamedLineLength is the length of the longest line streamCommand can
+// read, e.g. a commit subject.
+const maxStreamedLineLength = 1024 * 1024
 
-		var exitErr *exec.ExitError
-		exitCode := 0
+// streamCommand runs a git command and passes its standard output to handle
+// line by line while it runs, rather than collecting it into one string. It
+// returns the command string.
+func (g *GitCommands) streamCommand(handle func(line string), args ...string) (string, error) {
+	cmdStr := "git " + strings.Join(args, " ")
+	log.Printf("Executing command: %s", cmdStr)
 
-		if errors.As(err, &exitErr) {
-			exitCode = exitErr.ExitCode()
+	ctx, timeout, cancel := g.commandContext(false)
+	defer cancel()
+
+	cmd := g.command(ctx, nil, args)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return cmdStr, err
+	}
+	if err := cmd.Start(); err != nil {
+		if ctxErr := contextError(ctx, cmdStr, timeout); ctxErr != nil {
+			return cmdStr, ctxErr
 		}
+		return cmdStr, err
+	}
 
-		gitMsg := strings.TrimSpace(string(output))
-		gitMsg = strings.TrimPrefix(gitMsg, "fatal: ")
-		gitMsg = strings.TrimPrefix(gitMsg, "error: ")
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(nil, maxStreamedLineLength)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		// Drain the output so that git can exit.
+		_, _ = io.Copy(io.Discard, stdout)
+	}
 
-		detailedError := fmt.Errorf("[ERROR - %d] %s", exitCode, gitMsg)
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := contextError(ctx, cmdStr, timeout); ctxErr != nil {
+			return cmdStr, ctxErr
+		}
+		return cmdStr, commandError(args, err, "", stderr.String())
+	}
+	return cmdStr, scanErr
+}
 
-		return "", cmdStr, detailedError
+// contextError returns the error for a git process that was killed because
+// its context is done, or nil if the context is not done.
+func contextError(ctx context.Context, cmdStr string, timeout time.Duration) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		log.Printf("Timed out after %s: %s", timeout, cmdStr)
+		return fmt.Errorf("%s timed out after %s: %w", cmdStr, timeout, ctx.Err())
+	default:
+		log.Printf("Cancelled: %s", cmdStr)
+		return fmt.Errorf("%s was cancelled: %w", cmdStr, ctx.Err())
 	}
+}
 
-	return string(output), cmdStr, nil
+// commandError turns the error of a failed git command into a GitError that
+// carries its exit code, its output and the kind of error git reported.
+func commandError(args []string, err error, stdout, stderr string) error {
+	log.Printf("Error: %v, Stdout: %s, Stderr: %s", err, stdout, stderr)
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &GitError{
+		Args:     args,
+		ExitCode: exitCode,
+		Stdout:   stdout,
+		Stderr:   stderr,
+		Kind:     classifyError(stdout, stderr),
+		Err:      err,
+	}
 }
@@ -1,10 +1,15 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewGitCommands(t *testing.T) {
@@ -137,6 +142,48 @@ func TestGitCommands_Log(t *testing.T) {
 	if !strings.Contains(log, commitMessage) {
 		t.Errorf("expected log to contain commit message, got: %s", log)
 	}
+
+	createAndCommitFile(t, g, "log-test.txt", "changed", "Second commit for log test")
+	logs, err := g.GetCommitLogsGraph(0, 2)
+	if err != nil {
+		t.Fatalf("GetCommitLogsGraph() failed: %v", err)
+	}
+	if len(logs) != 2 || logs[0].Subject != "Second commit for log test" || logs[1].Subject != commitMessage {
+		t.Errorf("expected the two latest commits, got %+v", logs)
+	}
+	if next, err := g.GetCommitLogsGraph(2, 2); err != nil || len(next) != 1 || next[0].Hash != logs[1].Parents[0] {
+		t.Errorf("expected the page after the two latest commits to hold the first one, got %+v, %v", next, err)
+	}
+	if logs, err = g.GetCommitLogsGraph(0, 0); err != nil || len(logs) != 3 {
+		t.Fatalf("expected all 3 commits, got %+v, %v", logs, err)
+	}
+	if len(logs[0].Parents) != 1 || logs[0].Parents[0] != logs[1].Hash || len(logs[2].Parents) != 0 {
+		t.Errorf("expected each commit to have the next one as parent, got %+v", logs)
+	}
+
+	if _, _, err := g.ManageTag(TagOptions{Create: true, Name: "v1.0", Commit: logs[1].Hash}); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	if logs, err = g.GetCommitLogsGraph(0, 0); err != nil {
+		t.Fatalf("GetCommitLogsGraph() failed: %v", err)
+	}
+	head := logs[0]
+	if head.AuthorName != "Test User" || head.AuthorEmail != "test@example.com" || head.CommitterName != "Test User" {
+		t.Errorf("unexpected author or committer: %+v", head)
+	}
+	if time.Since(head.AuthorDate) > time.Hour || head.CommitterDate.IsZero() {
+		t.Errorf("expected recent dates, got %v and %v", head.AuthorDate, head.CommitterDate)
+	}
+	if len(head.Refs) != 1 || !head.Refs[0].Head || head.Refs[0].Kind != RefBranch {
+		t.Errorf("expected HEAD to point at the branch of the latest commit, got %+v", head.Refs)
+	}
+	if want := []Ref{{Name: "v1.0", Kind: RefTag}}; !reflect.DeepEqual(logs[1].Refs, want) {
+		t.Errorf("expected the tag on the second commit, got %+v", logs[1].Refs)
+	}
+
+	if _, err := g.streamCommand(func(string) {}, "log", "--no-such-option"); err == nil {
+		t.Error("expected an error for an invalid option")
+	}
 }
 
 func TestGitCommands_Diff(t *testing.T) {
@@ -186,6 +233,84 @@ func TestGitCommands_Commit(t *testing.T) {
 	}
 }
 
+func TestGitCommands_Context(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewGitCommands().WithContext(ctx)
+	if _, err := g.GetStatus(StatusOptions{}); err != nil {
+		t.Fatalf("GetStatus() failed before cancelling: %v", err)
+	}
+	cancel()
+	if _, err := g.GetStatus(StatusOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled error, got %v", err)
+	}
+	if _, err := g.GetCommitLogsGraph(0, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled streamed command, got %v", err)
+	}
+	if _, err := NewGitCommands().GetStatus(StatusOptions{}); err != nil {
+		t.Errorf("expected the original commands to be unaffected, got %v", err)
+	}
+
+	// A hook that hangs is killed once the command timeout passes.
+	hook := filepath.Join(".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nsleep 10\n"), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+	if err := os.WriteFile("hang.txt", []byte("content"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	g = NewGitCommands().WithTimeouts(Timeouts{Command: 200 * time.Millisecond})
+	if _, _, err := g.AddFiles([]string{"hang.txt"}); err != nil {
+		t.Fatalf("AddFiles() failed: %v", err)
+	}
+	start := time.Now()
+	_, _, err := g.Commit(CommitOptions{Message: "Hangs"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timed out error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the hook to be killed, took %s", elapsed)
+	}
+}
+
+func TestGitCommands_RepoPath(t *testing.T) {
+	repoPath, cleanup := setupRemoteRepo(t)
+	defer cleanup()
+
+	// The commands run in the repository without changing the directory.
+	g := NewGitCommands().WithRepoPath(repoPath)
+	repoName, _, err := g.GetRepoInfo()
+	if err != nil {
+		t.Fatalf("GetRepoInfo() failed: %v", err)
+	}
+	if repoName != filepath.Base(repoPath) {
+		t.Errorf("expected repository %s, got %s", filepath.Base(repoPath), repoName)
+	}
+	gitDir, err := g.GetGitRepoPath()
+	if err != nil || gitDir != filepath.Join(repoPath, ".git") {
+		t.Errorf("expected the absolute git directory, got %q, %v", gitDir, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoPath, "env.txt"), []byte("content"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, _, err := g.AddFiles([]string{"env.txt"}); err != nil {
+		t.Fatalf("AddFiles() failed: %v", err)
+	}
+	if _, _, err := g.WithEnv("GIT_AUTHOR_NAME=Env Author").Commit(CommitOptions{Message: "With env"}); err != nil {
+		t.Fatalf("Commit() failed: %v", err)
+	}
+	output, err := g.ShowLog(LogOptions{MaxCount: 1, Format: "%an"})
+	if err != nil {
+		t.Fatalf("ShowLog() failed: %v", err)
+	}
+	if strings.TrimSpace(output) != "Env Author" {
+		t.Errorf("expected the author from the environment, got %q", output)
+	}
+}
+
 func TestGitCommands_BranchAndCheckout(t *testing.T) {
 	_, cleanup := setupTestRepo(t)
 	defer cleanup()
@@ -349,3 +474,908 @@ func TestGitCommands_Stash(t *testing.T) {
 		t.Errorf("Stash() apply failed: %v", err)
 	}
 }
+
+func TestGitCommands_ApplyPatch(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	createAndCommitFile(t, g, "hunks.txt", strings.Join(lines, "\n")+"\n", "Initial commit for hunk test")
+
+	// Change the first and the last line so that the diff has two hunks.
+	lines[0] = "first changed"
+	lines[19] = "last changed"
+	if err := os.WriteFile("hunks.txt", []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+
+	diff, err := g.GetFileDiff("hunks.txt", false)
+	if err != nil {
+		t.Fatalf("GetFileDiff() failed: %v", err)
+	}
+	if len(diff.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(diff.Hunks))
+	}
+
+	// Stage only the first hunk.
+	if _, _, err := g.ApplyPatch(diff.HunkPatch(0), ApplyOptions{Cached: true}); err != nil {
+		t.Fatalf("ApplyPatch() failed: %v", err)
+	}
+	staged, err := g.GetFileDiff("hunks.txt", true)
+	if err != nil {
+		t.Fatalf("GetFileDiff() cached failed: %v", err)
+	}
+	if len(staged.Hunks) != 1 || !strings.Contains(strings.Join(staged.Hunks[0].Lines, "\n"), "+first changed") {
+		t.Fatalf("expected only the first hunk to be staged, got: %+v", staged.Hunks)
+	}
+
+	// Unstage it again by applying the staged hunk in reverse.
+	if _, _, err := g.ApplyPatch(staged.HunkPatch(0), ApplyOptions{Cached: true, Reverse: true}); err != nil {
+		t.Fatalf("ApplyPatch() reverse failed: %v", err)
+	}
+	staged, err = g.GetFileDiff("hunks.txt", true)
+	if err != nil {
+		t.Fatalf("GetFileDiff() cached failed: %v", err)
+	}
+	if len(staged.Hunks) != 0 {
+		t.Errorf("expected no staged hunks after unstaging, got %d", len(staged.Hunks))
+	}
+}
+
+func TestGitCommands_ApplyLinesPatch(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "lines.txt", "one\ntwo\nthree\nfour\nfive\n", "Initial commit for line staging test")
+	if err := os.WriteFile("lines.txt", []byte("one\nTWO\nthree\nFOUR\nfive\n"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+
+	diff, err := g.GetFileDiff("lines.txt", false)
+	if err != nil {
+		t.Fatalf("GetFileDiff() failed: %v", err)
+	}
+	if len(diff.Hunks) != 1 {
+		t.Fatalf("expected a single hunk, got %d", len(diff.Hunks))
+	}
+
+	// Select only the change of the second line.
+	selected := map[int]bool{}
+	for i, line := range diff.Hunks[0].Lines {
+		if line == "-two" || line == "+TWO" {
+			selected[i] = true
+		}
+	}
+	if _, _, err := g.ApplyPatch(diff.LinesPatch(0, selected, false), ApplyOptions{Cached: true}); err != nil {
+		t.Fatalf("ApplyPatch() failed: %v", err)
+	}
+
+	staged, err := g.ShowDiff(DiffOptions{Cached: true})
+	if err != nil {
+		t.Fatalf("ShowDiff() failed: %v", err)
+	}
+	if !strings.Contains(staged, "+TWO") || strings.Contains(staged, "+FOUR") {
+		t.Errorf("expected only the second line to be staged, got: %s", staged)
+	}
+}
+
+func TestGitCommands_GetRepoStatus(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "a -> b.txt", "content", "Add file with arrow in its name")
+
+	if _, err := g.MoveFile("a -> b.txt", "ünïcödé.txt"); err != nil {
+		t.Fatalf("MoveFile() failed: %v", err)
+	}
+	if err := os.WriteFile("new file.txt", []byte("new"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	status, err := g.GetRepoStatus()
+	if err != nil {
+		t.Fatalf("GetRepoStatus() failed: %v", err)
+	}
+	if status.Branch.Head != "master" {
+		t.Errorf("expected branch master, got %q", status.Branch.Head)
+	}
+
+	files := map[string]FileStatus{}
+	for _, f := range status.Files {
+		files[f.Path] = f
+	}
+
+	renamed, ok := files["ünïcödé.txt"]
+	if !ok || !renamed.IsRenamed() || renamed.OrigPath != "a -> b.txt" {
+		t.Errorf("expected rename from %q, got %+v", "a -> b.txt", renamed)
+	}
+	if untracked, ok := files["new file.txt"]; !ok || !untracked.IsUntracked() {
+		t.Errorf("expected untracked file, got %+v", untracked)
+	}
+}
+
+func TestGitCommands_InteractiveRebase(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "a.txt", "a", "first")
+	createAndCommitFile(t, g, "b.txt", "b", "second")
+	createAndCommitFile(t, g, "c.txt", "c", "third")
+	createAndCommitFile(t, g, "d.txt", "d", "fourth")
+
+	output, _, err := g.executeCommand("rev-parse", "--short", "HEAD~2")
+	if err != nil {
+		t.Fatalf("failed to resolve base commit: %v", err)
+	}
+	base := strings.TrimSpace(output)
+
+	todo, err := g.GetRebaseCommits(base)
+	if err != nil {
+		t.Fatalf("GetRebaseCommits() failed: %v", err)
+	}
+	if len(todo) != 3 || todo[0].Subject != "second" || todo[2].Subject != "fourth" {
+		t.Fatalf("unexpected todo list: %+v", todo)
+	}
+
+	// Reword "second", squash "third" into it and move "fourth" before them.
+	todo[0].Action = RebaseReword
+	todo[0].Message = "second, reworded"
+	todo[1].Action = RebaseFixup
+	todo = []RebaseTodoItem{todo[2], todo[0], todo[1]}
+
+	if output, _, err := g.InteractiveRebase(base, todo); err != nil {
+		t.Fatalf("InteractiveRebase() failed: %v\nOutput: %s", err, output)
+	}
+
+	subjects, _, err := g.executeCommand("log", "--format=%s")
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	expected := "second, reworded\nfourth\nfirst\nInitial commit"
+	if strings.TrimSpace(subjects) != expected {
+		t.Errorf("expected log %q, got %q", expected, strings.TrimSpace(subjects))
+	}
+	if _, err := os.Stat("c.txt"); err != nil {
+		t.Errorf("expected fixup to keep the changes of the commit: %v", err)
+	}
+	if inProgress, err := g.IsRebaseInProgress(); err != nil || inProgress {
+		t.Errorf("expected no rebase in progress, got %v (err: %v)", inProgress, err)
+	}
+
+	if _, _, err := g.InteractiveRebase(base, []RebaseTodoItem{{Action: RebaseSquash, SHA: "abc", Subject: "x"}}); err == nil {
+		t.Error("expected error when the first commit is squashed")
+	}
+
+	// A rebase that stops before a reword keeps its message in the state of
+	// the rebase, which git removes once the rebase is over.
+	if todo, err = g.GetRebaseCommits(base); err != nil || len(todo) != 2 {
+		t.Fatalf("unexpected todo list: %+v, %v", todo, err)
+	}
+	todo[0].Action = RebaseEdit
+	todo[1].Action = RebaseReword
+	todo[1].Message = "reworded after an edit"
+	if output, _, err := g.InteractiveRebase(base, todo); err != nil {
+		t.Fatalf("InteractiveRebase() failed: %v\nOutput: %s", err, output)
+	}
+	gitDir, _ := g.GetGitRepoPath()
+	if _, err := os.Stat(filepath.Join(gitDir, "rebase-merge", rebaseMessagePrefix+todo[1].SHA)); err != nil {
+		t.Errorf("expected the reword message in the state of the stopped rebase: %v", err)
+	}
+	if _, _, err := g.executeCommandWithOptions(execOptions{env: []string{nonInteractiveEditorEnv}}, "rebase", "--continue"); err != nil {
+		t.Fatalf("failed to continue rebase: %v", err)
+	}
+	if subject, _, _ := g.executeCommand("log", "-1", "--format=%s"); strings.TrimSpace(subject) != "reworded after an edit" {
+		t.Errorf("expected the reword to be applied after continuing, got %q", subject)
+	}
+	if inProgress, _ := g.IsRebaseInProgress(); inProgress {
+		t.Error("expected the rebase to be over")
+	}
+}
+
+func TestGitCommands_GetRebaseCommitsTopoOrder(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	output, _, err := g.executeCommand("rev-parse", "--short", "HEAD")
+	if err != nil {
+		t.Fatalf("failed to resolve base commit: %v", err)
+	}
+	base := strings.TrimSpace(output)
+
+	// The commits of a side branch are dated between those of the main branch.
+	commitAt := func(date, name string) {
+		t.Helper()
+		dated := g.WithEnv("GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		createAndCommitFile(t, dated, name+".txt", name, name)
+	}
+	checkout := func(args ...string) {
+		t.Helper()
+		if _, _, err := g.executeCommand(append([]string{"checkout"}, args...)...); err != nil {
+			t.Fatalf("checkout failed: %v", err)
+		}
+	}
+	checkout("-b", "side")
+	commitAt("2024-01-01T10:00:00", "side1")
+	checkout("-")
+	commitAt("2024-01-01T11:00:00", "main1")
+	checkout("side")
+	commitAt("2024-01-01T12:00:00", "side2")
+	checkout("-")
+	commitAt("2024-01-01T13:00:00", "main2")
+	if _, _, err := g.executeCommand("merge", "--no-ff", "--no-edit", "side"); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	todo, err := g.GetRebaseCommits(base)
+	if err != nil {
+		t.Fatalf("GetRebaseCommits() failed: %v", err)
+	}
+	var subjects []string
+	for _, item := range todo {
+		subjects = append(subjects, item.Subject)
+	}
+	// Each branch is replayed in one piece, as git lists it.
+	got := strings.Join(subjects, " ")
+	if got != "Initial commit side1 side2 main1 main2" && got != "Initial commit main1 main2 side1 side2" {
+		t.Errorf("expected the commits of each branch together, got %v", subjects)
+	}
+}
+
+func TestGitCommands_ResolveConflicts(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "a.txt", "base\n", "base commit")
+	createAndCommitFile(t, g, "b.txt", "base\n", "base commit for b")
+
+	if _, _, err := g.ManageBranch(BranchOptions{Create: true, Name: "feature"}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if _, _, err := g.Checkout("feature"); err != nil {
+		t.Fatalf("failed to checkout branch: %v", err)
+	}
+	createAndCommitFile(t, g, "a.txt", "feature\n", "feature change to a")
+	createAndCommitFile(t, g, "b.txt", "feature\n", "feature change to b")
+
+	if _, _, err := g.Checkout("master"); err != nil {
+		t.Fatalf("failed to checkout master: %v", err)
+	}
+	createAndCommitFile(t, g, "a.txt", "master\n", "master change to a")
+	createAndCommitFile(t, g, "b.txt", "master\n", "master change to b")
+
+	if _, err := g.Merge(MergeOptions{BranchName: "feature"}); err == nil {
+		t.Fatal("expected merge to stop on conflicts")
+	}
+
+	file, err := g.GetConflictedFile("a.txt")
+	if err != nil {
+		t.Fatalf("GetConflictedFile() failed: %v", err)
+	}
+	blocks := file.Blocks()
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 conflict block, got %d", len(blocks))
+	}
+	if _, _, err := g.ResolveConflictedFile(file); err == nil {
+		t.Error("expected error for unresolved file")
+	}
+	blocks[0].Resolution = ResolutionBoth
+	if _, _, err := g.ResolveConflictedFile(file); err != nil {
+		t.Fatalf("ResolveConflictedFile() failed: %v", err)
+	}
+
+	if _, _, err := g.CheckoutConflictSide("b.txt", SideTheirs); err != nil {
+		t.Fatalf("CheckoutConflictSide() failed: %v", err)
+	}
+
+	for path, want := range map[string]string{"a.txt": "master\nfeature\n", "b.txt": "feature\n"} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("got %s content %q, want %q", path, content, want)
+		}
+	}
+
+	status, err := g.GetRepoStatus()
+	if err != nil {
+		t.Fatalf("GetRepoStatus() failed: %v", err)
+	}
+	for _, f := range status.Files {
+		if f.IsConflicted() {
+			t.Errorf("expected %s to be resolved and staged, got %s", f.Path, f.Code())
+		}
+	}
+}
+
+func TestGitCommands_GetRepoState(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "file.txt", "base\n", "base commit")
+	if _, _, err := g.ManageBranch(BranchOptions{Create: true, Name: "feature"}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if _, _, err := g.Checkout("feature"); err != nil {
+		t.Fatalf("failed to checkout branch: %v", err)
+	}
+	createAndCommitFile(t, g, "file.txt", "feature\n", "feature commit")
+	if _, _, err := g.Checkout("master"); err != nil {
+		t.Fatalf("failed to checkout master: %v", err)
+	}
+	createAndCommitFile(t, g, "file.txt", "master\n", "master commit")
+
+	assertState := func(wantOp RepoOperation, wantBranch string, wantDetached bool) *RepoState {
+		t.Helper()
+		state, err := g.GetRepoState()
+		if err != nil {
+			t.Fatalf("GetRepoState() failed: %v", err)
+		}
+		if state.Operation != wantOp || state.Branch != wantBranch || state.Detached != wantDetached {
+			t.Errorf("got state %+v, want operation %q on branch %q (detached: %v)", state, wantOp, wantBranch, wantDetached)
+		}
+		return state
+	}
+
+	assertState(OperationNone, "master", false)
+
+	if _, err := g.Merge(MergeOptions{BranchName: "feature"}); err == nil {
+		t.Fatal("expected merge to stop on conflicts")
+	}
+	if state := assertState(OperationMerge, "master", false); state.Target == "" {
+		t.Error("expected the merged commit to be set")
+	}
+	if _, _, err := g.SkipOperation(OperationMerge); err == nil {
+		t.Error("expected error when skipping a merge")
+	}
+	if _, _, err := g.AbortOperation(OperationMerge); err != nil {
+		t.Fatalf("AbortOperation() failed: %v", err)
+	}
+
+	if _, err := g.Rebase(RebaseOptions{BranchName: "feature"}); err == nil {
+		t.Fatal("expected rebase to stop on conflicts")
+	}
+	if state := assertState(OperationRebase, "master", true); state.Step != 1 || state.Total != 1 {
+		t.Errorf("expected rebase progress 1/1, got %d/%d", state.Step, state.Total)
+	}
+	if _, _, err := g.SkipOperation(OperationRebase); err != nil {
+		t.Fatalf("SkipOperation() failed: %v", err)
+	}
+
+	// Skipping the only commit finished the rebase onto feature.
+	assertState(OperationNone, "master", false)
+
+	if _, _, err := g.Checkout("HEAD~1"); err != nil {
+		t.Fatalf("failed to detach HEAD: %v", err)
+	}
+	assertState(OperationNone, "", true)
+
+	if _, _, err := g.executeCommand("bisect", "start"); err != nil {
+		t.Fatalf("failed to start bisect: %v", err)
+	}
+	assertState(OperationBisect, "", true)
+	if _, _, err := g.AbortOperation(OperationBisect); err != nil {
+		t.Fatalf("AbortOperation() failed: %v", err)
+	}
+}
+
+func TestGitCommands_CherryPick(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	if _, _, err := g.ManageBranch(BranchOptions{Create: true, Name: "feature"}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if _, _, err := g.Checkout("feature"); err != nil {
+		t.Fatalf("failed to checkout branch: %v", err)
+	}
+	createAndCommitFile(t, g, "fix1.txt", "fix1", "first fix")
+	createAndCommitFile(t, g, "fix2.txt", "fix2", "second fix")
+	createAndCommitFile(t, g, "conflict.txt", "feature", "conflicting fix")
+
+	shas, _, err := g.executeCommand("log", "--reverse", "--format=%h", "master..feature")
+	if err != nil {
+		t.Fatalf("failed to list commits: %v", err)
+	}
+	commits := strings.Fields(shas)
+
+	if _, _, err := g.Checkout("master"); err != nil {
+		t.Fatalf("failed to checkout master: %v", err)
+	}
+	createAndCommitFile(t, g, "conflict.txt", "master", "master change")
+
+	if _, _, err := g.CherryPick(commits[:2]); err != nil {
+		t.Fatalf("CherryPick() failed: %v", err)
+	}
+	subjects, _, _ := g.executeCommand("log", "-2", "--format=%s")
+	if strings.TrimSpace(subjects) != "second fix\nfirst fix" {
+		t.Errorf("expected commits to be picked in order, got %q", subjects)
+	}
+
+	if _, _, err := g.CherryPick(commits[2:]); err == nil {
+		t.Fatal("expected cherry-pick to stop on conflicts")
+	}
+	state, err := g.GetRepoState()
+	if err != nil || state.Operation != OperationCherryPick {
+		t.Fatalf("expected cherry-pick in progress, got %+v (err: %v)", state, err)
+	}
+	if _, _, err := g.AbortOperation(OperationCherryPick); err != nil {
+		t.Fatalf("AbortOperation() failed: %v", err)
+	}
+}
+
+func TestGitCommands_Bisect(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	for i := 1; i <= 8; i++ {
+		content := fmt.Sprintf("pass %d", i)
+		if i >= 6 {
+			content = fmt.Sprintf("fail %d", i)
+		}
+		createAndCommitFile(t, g, "state.txt", content, fmt.Sprintf("commit %d", i))
+	}
+	firstBad, _, err := g.executeCommand("rev-parse", "HEAD~2")
+	if err != nil {
+		t.Fatalf("failed to resolve commit: %v", err)
+	}
+
+	if _, _, err := g.BisectStart("HEAD"); err != nil {
+		t.Fatalf("BisectStart() failed: %v", err)
+	}
+	if _, _, err := g.BisectMark(BisectGood, "HEAD~7"); err != nil {
+		t.Fatalf("BisectMark() failed: %v", err)
+	}
+
+	state, err := g.GetRepoState()
+	if err != nil {
+		t.Fatalf("GetRepoState() failed: %v", err)
+	}
+	if state.Operation != OperationBisect || state.Bisect == nil || !state.Bisect.HasRange() {
+		t.Fatalf("expected bisect with a known range, got %+v", state)
+	}
+	if state.Bisect.Remaining == 0 || state.Bisect.Steps == 0 || state.Bisect.FirstBad != "" {
+		t.Errorf("expected remaining revisions to test, got %+v", state.Bisect)
+	}
+
+	var output strings.Builder
+	if _, _, err := g.BisectRun("grep -q pass state.txt", &output); err != nil {
+		t.Fatalf("BisectRun() failed: %v", err)
+	}
+	if !strings.Contains(output.String(), "is the first bad commit") {
+		t.Errorf("expected streamed bisect output, got %q", output.String())
+	}
+
+	bisect, err := g.GetBisectState()
+	if err != nil {
+		t.Fatalf("GetBisectState() failed: %v", err)
+	}
+	if bisect.FirstBad != strings.TrimSpace(firstBad) {
+		t.Errorf("got first bad commit %q, want %q", bisect.FirstBad, strings.TrimSpace(firstBad))
+	}
+}
+
+func TestGitCommands_ReflogUndoRedo(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	head := func() string {
+		t.Helper()
+		sha, _, err := g.executeCommand("rev-parse", "--short", "HEAD")
+		if err != nil {
+			t.Fatalf("failed to resolve HEAD: %v", err)
+		}
+		return strings.TrimSpace(sha)
+	}
+	undo := func(redo bool) {
+		t.Helper()
+		entries, err := g.GetReflog()
+		if err != nil {
+			t.Fatalf("GetReflog() failed: %v", err)
+		}
+		move := FindUndo(entries)
+		if redo {
+			move = FindRedo(entries)
+		}
+		if move == nil {
+			t.Fatalf("expected something to undo or redo (redo: %v) in %+v", redo, entries)
+		}
+		if _, _, err := g.UndoHeadMove(*move, redo); err != nil {
+			t.Fatalf("UndoHeadMove() failed: %v", err)
+		}
+	}
+
+	initial := head()
+	createAndCommitFile(t, g, "a.txt", "a", "add a")
+	afterCommit := head()
+	if _, _, err := g.Commit(CommitOptions{Message: "add a (amended)", Amend: true}); err != nil {
+		t.Fatalf("failed to amend commit: %v", err)
+	}
+	afterAmend := head()
+	if _, _, err := g.ResetToCommit(initial); err != nil {
+		t.Fatalf("failed to reset: %v", err)
+	}
+
+	entries, err := g.GetReflog()
+	if err != nil || len(entries) == 0 || entries[0].Action != "reset" {
+		t.Fatalf("expected the reset as the latest reflog entry, got %+v (err: %v)", entries, err)
+	}
+
+	// Undoing steps back through the reset, the amend and the commit.
+	for _, want := range []string{afterAmend, afterCommit, initial} {
+		undo(false)
+		if got := head(); got != want {
+			t.Errorf("after undo got HEAD %s, want %s", got, want)
+		}
+	}
+
+	// Redoing replays them in order.
+	for _, want := range []string{afterCommit, afterAmend} {
+		undo(true)
+		if got := head(); got != want {
+			t.Errorf("after redo got HEAD %s, want %s", got, want)
+		}
+	}
+
+	// Checkouts are undone by checking out the previous branch.
+	if _, _, err := g.ManageBranch(BranchOptions{Create: true, Name: "feature"}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if _, _, err := g.Checkout("feature"); err != nil {
+		t.Fatalf("failed to checkout branch: %v", err)
+	}
+	undo(false)
+	if branch, _, _ := g.executeCommand("branch", "--show-current"); strings.TrimSpace(branch) != "master" {
+		t.Errorf("expected undo to check out master again, got %q", branch)
+	}
+
+	// A rebase is undone as a whole.
+	createAndCommitFile(t, g, "b.txt", "b", "add b")
+	beforeRebase := head()
+	todo, err := g.GetRebaseCommits(afterAmend)
+	if err != nil {
+		t.Fatalf("GetRebaseCommits() failed: %v", err)
+	}
+	todo[0].Action = RebaseDrop
+	if _, _, err := g.InteractiveRebase(afterAmend, todo); err != nil {
+		t.Fatalf("InteractiveRebase() failed: %v", err)
+	}
+	undo(false)
+	if got := head(); got != beforeRebase {
+		t.Errorf("after undoing the rebase got HEAD %s, want %s", got, beforeRebase)
+	}
+}
+
+func TestGitCommands_Remotes(t *testing.T) {
+	remotePath, cleanupRemote := setupRemoteRepo(t)
+	defer cleanupRemote()
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	if _, _, err := g.ManageRemote(RemoteOptions{Add: true, Name: "upstream", URL: remotePath}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+	if _, _, err := g.ManageRemote(RemoteOptions{Rename: true, Name: "upstream", NewName: "origin"}); err != nil {
+		t.Fatalf("failed to rename remote: %v", err)
+	}
+	if _, _, err := g.ManageRemote(RemoteOptions{SetURL: true, Push: true, Name: "origin", URL: "/nonexistent"}); err != nil {
+		t.Fatalf("failed to set push URL: %v", err)
+	}
+
+	remotes, err := g.GetRemotes()
+	if err != nil {
+		t.Fatalf("GetRemotes() failed: %v", err)
+	}
+	want := []*Remote{{Name: "origin", FetchURL: remotePath, PushURL: "/nonexistent"}}
+	if !reflect.DeepEqual(remotes, want) {
+		t.Fatalf("got remotes %+v, want %+v", remotes[0], want[0])
+	}
+
+	if _, _, err := g.Fetch(FetchOptions{Remote: "origin"}); err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+	branches, err := g.GetRemoteBranches("origin")
+	if err != nil || len(branches) != 1 {
+		t.Fatalf("expected one remote branch, got %+v (err: %v)", branches, err)
+	}
+	if _, _, err := g.CheckoutRemoteBranch(branches[0], "tracking"); err != nil {
+		t.Fatalf("CheckoutRemoteBranch() failed: %v", err)
+	}
+	upstream, _, err := g.executeCommand("rev-parse", "--abbrev-ref", "tracking@{upstream}")
+	if err != nil || strings.TrimSpace(upstream) != branches[0].FullName() {
+		t.Errorf("expected tracking branch of %s, got %q (err: %v)", branches[0].FullName(), upstream, err)
+	}
+
+	if _, _, err := g.ManageRemote(RemoteOptions{Remove: true, Name: "origin"}); err != nil {
+		t.Fatalf("failed to remove remote: %v", err)
+	}
+	if remotes, _ := g.GetRemotes(); len(remotes) != 0 {
+		t.Errorf("expected no remotes after removal, got %+v", remotes)
+	}
+}
+
+func TestGitCommands_Tags(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	remotePath, err := os.MkdirTemp("", "git-bare-")
+	if err != nil {
+		t.Fatalf("failed to create remote dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(remotePath) }()
+
+	g := NewGitCommands()
+	if _, _, err := g.executeCommand("init", "--bare", remotePath); err != nil {
+		t.Fatalf("failed to init bare repository: %v", err)
+	}
+	if _, _, err := g.ManageRemote(RemoteOptions{Add: true, Name: "origin", URL: remotePath}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+
+	head, _, _ := g.executeCommand("rev-parse", "--short", "HEAD")
+	if _, _, err := g.ManageTag(TagOptions{Create: true, Name: "v1.0", Message: "Release 1.0"}); err != nil {
+		t.Fatalf("failed to create annotated tag: %v", err)
+	}
+	if _, _, err := g.ManageTag(TagOptions{Create: true, Name: "light", Commit: "HEAD"}); err != nil {
+		t.Fatalf("failed to create lightweight tag: %v", err)
+	}
+
+	tags, err := g.GetTags()
+	if err != nil || len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %+v (err: %v)", tags, err)
+	}
+	byName := map[string]*Tag{}
+	for _, tag := range tags {
+		byName[tag.Name] = tag
+	}
+	want := map[string]*Tag{
+		"v1.0":  {Name: "v1.0", Commit: strings.TrimSpace(head), Annotated: true, Message: "Release 1.0"},
+		"light": {Name: "light", Commit: strings.TrimSpace(head)},
+	}
+	if !reflect.DeepEqual(byName, want) {
+		t.Errorf("got tags %+v and %+v, want %+v and %+v", byName["v1.0"], byName["light"], want["v1.0"], want["light"])
+	}
+
+	remoteTags := func() string {
+		t.Helper()
+		output, _, err := g.executeCommand("ls-remote", "--tags", "origin")
+		if err != nil {
+			t.Fatalf("failed to list remote tags: %v", err)
+		}
+		return output
+	}
+	if _, _, err := g.Push(PushOptions{Remote: "origin", Tag: "v1.0"}); err != nil {
+		t.Fatalf("failed to push tag: %v", err)
+	}
+	if output := remoteTags(); !strings.Contains(output, "refs/tags/v1.0") || strings.Contains(output, "refs/tags/light") {
+		t.Errorf("expected only v1.0 on the remote, got %q", output)
+	}
+	if _, _, err := g.Push(PushOptions{Remote: "origin", Tag: "v1.0", Delete: true}); err != nil {
+		t.Fatalf("failed to delete remote tag: %v", err)
+	}
+	if output := remoteTags(); strings.Contains(output, "refs/tags/v1.0") {
+		t.Errorf("expected v1.0 to be deleted on the remote, got %q", output)
+	}
+
+	if _, _, err := g.ManageTag(TagOptions{Delete: true, Name: "light"}); err != nil {
+		t.Fatalf("failed to delete tag: %v", err)
+	}
+	if tags, _ := g.GetTags(); len(tags) != 1 {
+		t.Errorf("expected 1 tag after deletion, got %+v", tags)
+	}
+}
+
+func TestGitCommands_PushPullFetch(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	remotePath, err := os.MkdirTemp("", "git-bare-")
+	if err != nil {
+		t.Fatalf("failed to create remote dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(remotePath) }()
+
+	g := NewGitCommands()
+	if _, _, err := g.executeCommand("init", "--bare", remotePath); err != nil {
+		t.Fatalf("failed to init bare repository: %v", err)
+	}
+	if _, _, err := g.ManageRemote(RemoteOptions{Add: true, Name: "origin", URL: remotePath}); err != nil {
+		t.Fatalf("failed to add remote: %v", err)
+	}
+
+	var progress strings.Builder
+	if _, cmdStr, err := g.Push(PushOptions{Remote: "origin", Branch: "master", SetUpstream: true, Progress: &progress}); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	} else if !strings.Contains(cmdStr, "--progress") {
+		t.Errorf("expected progress to be requested, got %q", cmdStr)
+	}
+	if !strings.Contains(progress.String(), "Writing objects") {
+		t.Errorf("expected push progress to be streamed, got %q", progress.String())
+	}
+
+	// Another clone pushes a commit, which is then fetched and pulled.
+	clonePath, err := os.MkdirTemp("", "git-clone-")
+	if err != nil {
+		t.Fatalf("failed to create clone dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(clonePath) }()
+	if _, err := g.CloneRepository(remotePath, clonePath); err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+	if err := os.Chdir(clonePath); err != nil {
+		t.Fatalf("failed to change to clone: %v", err)
+	}
+	if err := runGitConfig(clonePath); err != nil {
+		t.Fatalf("failed to set git config: %v", err)
+	}
+	createAndCommitFile(t, g, "remote.txt", "remote", "remote change")
+	if _, _, err := g.Push(PushOptions{}); err != nil {
+		t.Fatalf("failed to push from clone: %v", err)
+	}
+	if err := os.Chdir(repoPath); err != nil {
+		t.Fatalf("failed to change back to repo: %v", err)
+	}
+
+	progress.Reset()
+	if _, _, err := g.Fetch(FetchOptions{All: true, Progress: &progress}); err != nil {
+		t.Fatalf("Fetch() failed: %v", err)
+	}
+	if !strings.Contains(progress.String(), "origin/master") {
+		t.Errorf("expected fetch output to be streamed, got %q", progress.String())
+	}
+	branches, err := g.GetBranches()
+	if err != nil {
+		t.Fatalf("GetBranches() failed: %v", err)
+	}
+	wantTracking := Tracking{Upstream: "origin/master", Behind: 1}
+	if len(branches) != 1 || branches[0].Tracking != wantTracking {
+		t.Errorf("got branches %+v, want master tracking %+v", branches, wantTracking)
+	}
+	if state, err := g.GetRepoState(); err != nil || state.Tracking != wantTracking {
+		t.Errorf("GetRepoState() = %+v, %v, want tracking %+v", state, err, wantTracking)
+	}
+	if _, _, err := g.Pull(PullOptions{Progress: &progress}); err != nil {
+		t.Fatalf("Pull() failed: %v", err)
+	}
+	if _, err := os.Stat("remote.txt"); err != nil {
+		t.Errorf("expected pulled file to exist: %v", err)
+	}
+
+	if _, _, err := g.UnsetUpstream("master"); err != nil {
+		t.Fatalf("UnsetUpstream() failed: %v", err)
+	}
+	if state, err := g.GetRepoState(); err != nil || state.Tracking.Upstream != "" {
+		t.Errorf("expected no upstream, got %+v, %v", state, err)
+	}
+	if _, _, err := g.SetUpstream("master", "origin/master"); err != nil {
+		t.Fatalf("SetUpstream() failed: %v", err)
+	}
+	if state, err := g.GetRepoState(); err != nil || state.Tracking != (Tracking{Upstream: "origin/master"}) {
+		t.Errorf("expected master to track origin/master, got %+v, %v", state, err)
+	}
+}
+
+func TestGitCommands_Blame(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "blame.txt", "one\ntwo\n", "Add blame file")
+	createAndCommitFile(t, g, "blame.txt", "one\nTWO\n", "Change second line")
+	if err := os.WriteFile("blame.txt", []byte("one\nTWO\nthree\n"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+
+	lines, err := g.BlameFile("blame.txt", "")
+	if err != nil {
+		t.Fatalf("BlameFile() failed: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	if lines[0].Commit.Summary != "Add blame file" || lines[0].Commit.Author != "Test User" {
+		t.Errorf("unexpected commit of the first line: %+v", lines[0].Commit)
+	}
+	changed := lines[1].Commit
+	if changed.Summary != "Change second line" || changed.Previous == "" {
+		t.Errorf("unexpected commit of the second line: %+v", changed)
+	}
+	if !lines[2].Commit.IsUncommitted() {
+		t.Errorf("expected the third line to be uncommitted, got %+v", lines[2].Commit)
+	}
+
+	// Blaming the parent shows the line before it was changed.
+	lines, err = g.BlameFile(changed.PreviousPath, changed.Previous)
+	if err != nil {
+		t.Fatalf("BlameFile() at the parent failed: %v", err)
+	}
+	if len(lines) != 2 || lines[1].Content != "two" {
+		t.Errorf("unexpected blame at the parent: %+v", lines)
+	}
+}
+
+func TestGitCommands_FileHistory(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	createAndCommitFile(t, g, "old.txt", "content\n", "Add old file")
+	createAndCommitFile(t, g, "other.txt", "other\n", "Add other file")
+	if _, err := g.MoveFile("old.txt", "new.txt"); err != nil {
+		t.Fatalf("MoveFile() failed: %v", err)
+	}
+	if _, _, err := g.Commit(CommitOptions{Message: "Rename file"}); err != nil {
+		t.Fatalf("failed to commit rename: %v", err)
+	}
+
+	logs, err := g.GetFileHistory("new.txt")
+	if err != nil {
+		t.Fatalf("GetFileHistory() failed: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected the history to follow the rename, got %+v", logs)
+	}
+	if logs[0].Subject != "Rename file" || logs[0].Path != "new.txt" {
+		t.Errorf("unexpected latest commit: %+v", logs[0])
+	}
+	if logs[1].Subject != "Add old file" || logs[1].Path != "old.txt" {
+		t.Errorf("unexpected first commit: %+v", logs[1])
+	}
+
+	diff, err := g.ShowCommitFile(logs[1].SHA, logs[1].Path)
+	if err != nil {
+		t.Fatalf("ShowCommitFile() failed: %v", err)
+	}
+	if !strings.Contains(diff, "old.txt") || strings.Contains(diff, "other.txt") {
+		t.Errorf("expected only the changes to old.txt, got %s", diff)
+	}
+}
+
+func TestParseTracking(t *testing.T) {
+	tests := map[string]Tracking{
+		"":                    {Upstream: "origin/main"},
+		"[ahead 2]":           {Upstream: "origin/main", Ahead: 2},
+		"[behind 1]":          {Upstream: "origin/main", Behind: 1},
+		"[ahead 3, behind 4]": {Upstream: "origin/main", Ahead: 3, Behind: 4},
+		"[gone]":              {Upstream: "origin/main", Gone: true},
+	}
+	for track, want := range tests {
+		if got := parseTracking("origin/main", track); got != want {
+			t.Errorf("parseTracking(%q) = %+v, want %+v", track, got, want)
+		}
+	}
+}
+
+func TestParseDecorations(t *testing.T) {
+	refs := parseDecorations("HEAD -> refs/heads/main, refs/remotes/origin/main, tag: refs/tags/v1.0, refs/stash")
+	want := []Ref{
+		{Name: "main", Kind: RefBranch, Head: true},
+		{Name: "origin/main", Kind: RefRemoteBranch},
+		{Name: "v1.0", Kind: RefTag},
+		{Name: "stash", Kind: RefOther},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got %+v, want %+v", refs, want)
+	}
+
+	if refs := parseDecorations("HEAD, refs/heads/feature"); len(refs) != 2 || refs[0].Kind != RefHead || refs[1].Head {
+		t.Errorf("expected a detached HEAD, got %+v", refs)
+	}
+	if refs := parseDecorations(""); len(refs) != 0 {
+		t.Errorf("expected no refs, got %+v", refs)
+	}
+}
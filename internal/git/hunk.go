@@ -0,0 +1,184 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeaderRegex matches a unified diff hunk header, e.g. "@@ -1,3 +1,4 @@ func main()".
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// FileDiff represents the diff of a single file.
+type FileDiff struct {
+	Header []string // The lines preceding the first hunk ("diff --git", "index", "---", "+++").
+	Hunks  []Hunk
+}
+
+// Hunk represents a single hunk of a unified diff.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Section  string   // The text following the closing "@@", usually the enclosing function.
+	Lines    []string // The body of the hunk, each line keeping its ' ', '+', '-' or '\' prefix.
+}
+
+// Header returns the "@@ ... @@" line of the hunk.
+func (h Hunk) Header() string {
+	header := fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	if h.Section != "" {
+		header += " " + h.Section
+	}
+	return header
+}
+
+// HunkPatch returns a patch containing only the hunk at the given index, suitable
+// for `git apply`.
+func (f *FileDiff) HunkPatch(index int) string {
+	if index < 0 || index >= len(f.Hunks) {
+		return ""
+	}
+	return f.patch(f.Hunks[index])
+}
+
+// LinesPatch returns a minimal patch that contains only the selected lines of
+// the hunk at the given index, as `git add -p` does in edit mode. Keys of selected
+// are indices into the hunk's Lines; only '+' and '-' lines can be selected.
+//
+// Unselected changes are either dropped or turned into context, depending on
+// whether they exist on the side the patch is applied to. Set reverse if the
+// patch will be applied with --reverse, e.g. to unstage lines from the index.
+// An empty string is returned if no changed lines are selected.
+func (f *FileDiff) LinesPatch(index int, selected map[int]bool, reverse bool) string {
+	if index < 0 || index >= len(f.Hunks) {
+		return ""
+	}
+	hunk := f.Hunks[index]
+
+	// Lines of this kind exist on the side the patch is applied to, so they
+	// must stay as context when they are not selected.
+	keepAsContext := byte('-')
+	if reverse {
+		keepAsContext = '+'
+	}
+
+	result := Hunk{OldStart: hunk.OldStart, NewStart: hunk.NewStart, Section: hunk.Section}
+	hasChanges := false
+	previousKept := false
+
+	for i, line := range hunk.Lines {
+		if line == "" {
+			continue
+		}
+		kind := line[0]
+		switch {
+		case kind == '\\':
+			// "\ No newline at end of file" belongs to the preceding line.
+			if previousKept {
+				result.Lines = append(result.Lines, line)
+			}
+			continue
+		case (kind == '+' || kind == '-') && selected[i]:
+			hasChanges = true
+		case kind == keepAsContext:
+			line = " " + line[1:]
+			kind = ' '
+		case kind == '+' || kind == '-':
+			previousKept = false
+			continue
+		}
+
+		result.Lines = append(result.Lines, line)
+		previousKept = true
+		if kind != '+' {
+			result.OldLines++
+		}
+		if kind != '-' {
+			result.NewLines++
+		}
+	}
+
+	if !hasChanges {
+		return ""
+	}
+	return f.patch(result)
+}
+
+// patch joins the file header and the given hunk into a complete patch.
+func (f *FileDiff) patch(h Hunk) string {
+	var builder strings.Builder
+	for _, line := range f.Header {
+		builder.WriteString(line + "\n")
+	}
+	builder.WriteString(h.Header() + "\n")
+	for _, line := range h.Lines {
+		builder.WriteString(line + "\n")
+	}
+	return builder.String()
+}
+
+// ParseDiff parses the raw output of `git diff` (without color) into a slice of
+// FileDiff structs, one per file.
+func ParseDiff(output string) []FileDiff {
+	var diffs []FileDiff
+	var current *FileDiff
+	var hunk *Hunk
+
+	flushHunk := func() {
+		if current != nil && hunk != nil {
+			current.Hunks = append(current.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			flushHunk()
+			if current != nil {
+				diffs = append(diffs, *current)
+			}
+			current = &FileDiff{Header: []string{line}}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if matches := hunkHeaderRegex.FindStringSubmatch(line); matches != nil {
+			flushHunk()
+			hunk = &Hunk{
+				OldStart: atoiOr(matches[1], 0),
+				OldLines: atoiOr(matches[2], 1),
+				NewStart: atoiOr(matches[3], 0),
+				NewLines: atoiOr(matches[4], 1),
+				Section:  matches[5],
+			}
+			continue
+		}
+
+		if hunk != nil {
+			hunk.Lines = append(hunk.Lines, line)
+		} else {
+			current.Header = append(current.Header, line)
+		}
+	}
+	flushHunk()
+	if current != nil {
+		diffs = append(diffs, *current)
+	}
+
+	return diffs
+}
+
+// atoiOr converts s to an int, returning fallback if s is empty or invalid.
+func atoiOr(s string, fallback int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/file.txt b/file.txt
+index 3b18e51..a1b2c3d 100644
+--- a/file.txt
++++ b/file.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+@@ -10 +10,2 @@ func main()
+ ten
++eleven
+\ No newline at end of file
+diff --git a/other.txt b/other.txt
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/other.txt
+@@ -0,0 +1 @@
++new
+`
+
+func TestParseDiff(t *testing.T) {
+	diffs := ParseDiff(sampleDiff)
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 file diffs, got %d", len(diffs))
+	}
+
+	first := diffs[0]
+	if len(first.Header) != 4 {
+		t.Errorf("expected 4 header lines, got %d: %v", len(first.Header), first.Header)
+	}
+	if len(first.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(first.Hunks))
+	}
+
+	second := first.Hunks[1]
+	if second.OldStart != 10 || second.OldLines != 1 || second.NewStart != 10 || second.NewLines != 2 {
+		t.Errorf("unexpected hunk range: %+v", second)
+	}
+	if second.Section != "func main()" {
+		t.Errorf("expected section %q, got %q", "func main()", second.Section)
+	}
+	if len(second.Lines) != 3 {
+		t.Errorf("expected 3 hunk lines, got %d: %v", len(second.Lines), second.Lines)
+	}
+
+	if got := diffs[1].Hunks[0].Header(); got != "@@ -0,0 +1,1 @@" {
+		t.Errorf("unexpected hunk header: %q", got)
+	}
+}
+
+func TestFileDiff_HunkPatch(t *testing.T) {
+	diff := ParseDiff(sampleDiff)[0]
+
+	patch := diff.HunkPatch(0)
+	if !strings.HasPrefix(patch, "diff --git a/file.txt b/file.txt\n") {
+		t.Errorf("patch should start with the file header, got: %s", patch)
+	}
+	if !strings.Contains(patch, "@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n") {
+		t.Errorf("patch should contain the first hunk, got: %s", patch)
+	}
+	if strings.Contains(patch, "eleven") {
+		t.Errorf("patch should not contain the second hunk, got: %s", patch)
+	}
+
+	if patch := diff.HunkPatch(5); patch != "" {
+		t.Errorf("expected empty patch for out of range hunk, got: %s", patch)
+	}
+}
+
+func TestFileDiff_LinesPatch(t *testing.T) {
+	diff := ParseDiff(sampleDiff)[0]
+
+	t.Run("stage an addition keeps removals as context", func(t *testing.T) {
+		patch := diff.LinesPatch(0, map[int]bool{2: true}, false)
+		want := "@@ -1,3 +1,4 @@\n one\n two\n+TWO\n three\n"
+		if !strings.HasSuffix(patch, want) {
+			t.Errorf("unexpected patch:\n%s\nwant suffix:\n%s", patch, want)
+		}
+	})
+
+	t.Run("unstage a removal keeps additions as context", func(t *testing.T) {
+		patch := diff.LinesPatch(0, map[int]bool{1: true}, true)
+		want := "@@ -1,4 +1,3 @@\n one\n-two\n TWO\n three\n"
+		if !strings.HasSuffix(patch, want) {
+			t.Errorf("unexpected patch:\n%s\nwant suffix:\n%s", patch, want)
+		}
+	})
+
+	t.Run("context lines alone produce no patch", func(t *testing.T) {
+		if patch := diff.LinesPatch(0, map[int]bool{0: true}, false); patch != "" {
+			t.Errorf("expected empty patch, got: %s", patch)
+		}
+	})
+}
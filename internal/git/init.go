@@ -21,6 +21,9 @@ func (g *GitCommands) InitRepository(path string) (string, error) {
 		)
 	}
 
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.repoPath, path)
+	}
 	absPath, _ := filepath.Abs(path)
 	return fmt.Sprintf("Initialized empty Git repository in %s", absPath), nil
 }
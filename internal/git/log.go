@@ -3,51 +3,129 @@ package git
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 )
 
-// CommitLog represents a single entry in the git log graph.
-// It can be a commit or a line representing the graph structure.
+// CommitLog represents a single commit of a log.
 type CommitLog struct {
-	Graph          string // The graph structure string.
-	SHA            string // The abbreviated commit hash.
+	Graph          string   // The graph structure string, in a file history.
+	Hash           string   // The full commit hash.
+	Parents        []string // The full hashes of the parent commits.
+	SHA            string   // The abbreviated commit hash.
+	AuthorName     string
+	AuthorEmail    string
+	AuthorDate     time.Time
 	AuthorInitials string // The initials of the commit author.
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+	Refs           []Ref  // The refs pointing at the commit.
 	Subject        string // The subject line of the commit message.
+	Path           string // The path of the file in this commit, in a file history.
+}
+
+// RefKind is the kind of a ref that decorates a commit.
+type RefKind int
+
+// Defines the kinds of refs.
+const (
+	RefBranch RefKind = iota
+	RefRemoteBranch
+	RefTag
+	RefHead  // HEAD, when it is detached.
+	RefOther // E.g. the stash.
+)
+
+// Ref is a ref that points at a commit of a log.
+type Ref struct {
+	Name string // The short name of the ref, e.g. "main" or "origin/main".
+	Kind RefKind
+	Head bool // Whether HEAD points at the branch.
 }
 
 // LogOptions specifies the options for the git log command.
 type LogOptions struct {
-	Oneline  bool
-	Graph    bool
-	All      bool
-	MaxCount int
-	Format   string
-	Color    string
-	Branch   string
+	Oneline   bool
+	Graph     bool
+	All       bool
+	TopoOrder bool // Shows no parents before all of their children, as --graph does.
+	MaxCount  int
+	Skip      int // Skips this many commits before showing any.
+	Format    string
+	Color     string
+	Branch    string
+	Path      string // Limits the log to commits that changed this path.
+	Follow    bool   // Continues the history of Path beyond renames.
+	NameOnly  bool
+	Decorate  string // How refs are named in %d and %D: "short", "full" or "no".
+}
+
+// GetCommitLogsGraph fetches the commits of all refs with their parents, in
+// the order the commit graph is drawn in, and returns them as a slice of
+// CommitLog structs. The graph itself is left to the caller to lay out. The
+// first skip commits are left out, so the log can be fetched a page at a time,
+// and at most limit commits are fetched, or all of them if limit is 0. The
+// output is parsed while git writes it.
+func (g *GitCommands) GetCommitLogsGraph(skip, limit int) ([]CommitLog, error) {
+	options := LogOptions{
+		Format:    commitGraphFormat,
+		Decorate:  "full", // Tells local branches from remote ones.
+		TopoOrder: true,
+		All:       true,
+		MaxCount:  limit,
+		Skip:      skip,
+	}
+
+	var logs []CommitLog
+	_, err := g.streamCommand(func(line string) {
+		if log, ok := parseCommitLog(line); ok {
+			logs = append(logs, log)
+		}
+	}, logArgs(options)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show log: %w", err)
+	}
+	return logs, nil
 }
 
-// GetCommitLogsGraph fetches the git log with a graph format and returns it as a
-// slice of CommitLog structs.
-func (g *GitCommands) GetCommitLogsGraph() ([]CommitLog, error) {
-	// A custom format with a unique delimiter is used to reliably parse the output.
-	format := "<COMMIT>%h|%an|%s"
+// GetFileHistory fetches the commits of HEAD that changed a file, following
+// its renames, with the path of the file in each commit.
+func (g *GitCommands) GetFileHistory(path string) ([]CommitLog, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
 	options := LogOptions{
-		Graph:  true,
-		Format: format,
-		Color:  "always",
-		All:    true,
+		Format:   "<COMMIT>%h|%an|%s",
+		Path:     path,
+		Follow:   true,
+		NameOnly: true,
 	}
 
 	output, err := g.ShowLog(options)
 	if err != nil {
 		return nil, err
 	}
-	return parseCommitLogs(strings.TrimSpace(output)), nil
+	return parseFileHistory(output), nil
 }
 
 // ShowLog executes the `git log` command with the given options and returns the raw output.
 func (g *GitCommands) ShowLog(options LogOptions) (string, error) {
+	output, _, err := g.executeCommand(logArgs(options)...)
+	if err != nil {
+		return string(output), fmt.Errorf(
+			"failed to show log: %w",
+			err,
+		)
+	}
+
+	return string(output), nil
+}
+
+// logArgs returns the arguments of the `git log` command for the given options.
+func logArgs(options LogOptions) []string {
 	args := []string{"log"}
 
 	if options.Format != "" {
@@ -62,50 +140,128 @@ func (g *GitCommands) ShowLog(options LogOptions) (string, error) {
 	if options.All {
 		args = append(args, "--all")
 	}
+	if options.TopoOrder {
+		args = append(args, "--topo-order")
+	}
+	if options.Decorate != "" {
+		args = append(args, "--decorate="+options.Decorate)
+	}
 	if options.MaxCount > 0 {
 		args = append(args, fmt.Sprintf("-%d", options.MaxCount))
 	}
+	if options.Skip > 0 {
+		args = append(args, fmt.Sprintf("--skip=%d", options.Skip))
+	}
 	if options.Color != "" {
 		args = append(args, fmt.Sprintf("--color=%s", options.Color))
 	}
+	if options.Follow {
+		args = append(args, "--follow")
+	}
+	if options.NameOnly {
+		args = append(args, "--name-only")
+	}
 	if options.Branch != "" {
 		args = append(args, options.Branch)
 	}
+	if options.Path != "" {
+		args = append(args, "--", options.Path)
+	}
+	return args
+}
+
+// commitGraphFormat is the format of the commits fetched for the commit graph,
+// with fields separated by the unit separator.
+const commitGraphFormat = "%H%x1f%P%x1f%h%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%D%x1f%s"
 
-	output, _, err := g.executeCommand(args...)
+// parseCommitLog parses a line of the log in commitGraphFormat. It reports
+// false if the line is not a commit.
+func parseCommitLog(line string) (CommitLog, bool) {
+	fields := strings.SplitN(line, "\x1f", 11)
+	if len(fields) != 11 {
+		return CommitLog{}, false
+	}
+	return CommitLog{
+		Hash:           fields[0],
+		Parents:        strings.Fields(fields[1]),
+		SHA:            fields[2],
+		AuthorName:     fields[3],
+		AuthorEmail:    fields[4],
+		AuthorDate:     parseUnixTime(fields[5]),
+		AuthorInitials: getInitials(fields[3]),
+		CommitterName:  fields[6],
+		CommitterEmail: fields[7],
+		CommitterDate:  parseUnixTime(fields[8]),
+		Refs:           parseDecorations(fields[9]),
+		Subject:        fields[10],
+	}, true
+}
+
+// parseUnixTime parses a timestamp in seconds since the epoch, returning the
+// zero time if it is invalid.
+func parseUnixTime(s string) time.Time {
+	seconds, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		return string(output), fmt.Errorf(
-			"failed to show log: %w",
-			err,
-		)
+		return time.Time{}
 	}
+	return time.Unix(seconds, 0)
+}
 
-	return string(output), nil
+// parseDecorations parses the refs of a commit from the output of %D with
+// --decorate=full, e.g. "HEAD -> refs/heads/main, tag: refs/tags/v1.0".
+func parseDecorations(s string) []Ref {
+	var refs []Ref
+	for _, name := range strings.Split(s, ", ") {
+		ref := Ref{Kind: RefOther}
+		if branch, ok := strings.CutPrefix(name, "HEAD -> "); ok {
+			name, ref.Head = branch, true
+		}
+		if tag, ok := strings.CutPrefix(name, "tag: "); ok {
+			name = tag
+		}
+		switch {
+		case name == "":
+			continue
+		case name == "HEAD":
+			ref.Kind = RefHead
+		case strings.HasPrefix(name, "refs/heads/"):
+			ref.Kind = RefBranch
+		case strings.HasPrefix(name, "refs/remotes/"):
+			ref.Kind = RefRemoteBranch
+		case strings.HasPrefix(name, "refs/tags/"):
+			ref.Kind = RefTag
+		}
+		for _, prefix := range []string{"refs/heads/", "refs/remotes/", "refs/tags/", "refs/"} {
+			if short, ok := strings.CutPrefix(name, prefix); ok {
+				name = short
+				break
+			}
+		}
+		ref.Name = name
+		refs = append(refs, ref)
+	}
+	return refs
 }
 
-// parseCommitLogs processes the raw git log string into a slice of CommitLog structs.
-func parseCommitLogs(output string) []CommitLog {
+// parseFileHistory processes the output of a file history, where each commit
+// is followed by the path of the file in that commit.
+func parseFileHistory(output string) []CommitLog {
 	var logs []CommitLog
-	lines := strings.Split(output, "\n")
-
-	for _, line := range lines {
-		lineWithNodeReplaced := strings.ReplaceAll(line, "*", "○")
-
-		if strings.Contains(lineWithNodeReplaced, "<COMMIT>") {
-			parts := strings.SplitN(lineWithNodeReplaced, "<COMMIT>", 2)
-			graph := parts[0]
-			commitData := strings.SplitN(parts[1], "|", 3)
-
+	for _, line := range strings.Split(output, "\n") {
+		if commit, ok := strings.CutPrefix(line, "<COMMIT>"); ok {
+			commitData := strings.SplitN(commit, "|", 3)
 			if len(commitData) == 3 {
 				logs = append(logs, CommitLog{
-					Graph:          graph,
+					Graph:          "○",
 					SHA:            commitData[0],
 					AuthorInitials: getInitials(commitData[1]),
 					Subject:        commitData[2],
 				})
 			}
-		} else {
-			logs = append(logs, CommitLog{Graph: lineWithNodeReplaced})
+			continue
+		}
+		if line != "" && len(logs) > 0 && logs[len(logs)-1].Path == "" {
+			logs[len(logs)-1].Path = line
 		}
 	}
 	return logs
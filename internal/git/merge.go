@@ -31,7 +31,7 @@ func (g *GitCommands) Merge(options MergeOptions) (string, error) {
 
 	output, _, err := g.executeCommand(args...)
 	if err != nil {
-		return string(output), fmt.Errorf("failed to merge branch: %v", err)
+		return string(output), fmt.Errorf("failed to merge branch: %w", err)
 	}
 
 	return string(output), nil
@@ -43,11 +43,13 @@ type RebaseOptions struct {
 	Interactive bool
 	Abort       bool
 	Continue    bool
+	Skip        bool
 }
 
 // Rebase integrates changes from another branch.
 func (g *GitCommands) Rebase(options RebaseOptions) (string, error) {
 	args := []string{"rebase"}
+	var env []string
 
 	if options.Interactive {
 		args = append(args, "-i")
@@ -57,12 +59,17 @@ func (g *GitCommands) Rebase(options RebaseOptions) (string, error) {
 	}
 	if options.Continue {
 		args = append(args, "--continue")
+		// Keep the commit message as it is instead of opening an editor.
+		env = append(env, nonInteractiveEditorEnv)
 	}
-	if options.BranchName != "" && !options.Abort && !options.Continue {
+	if options.Skip {
+		args = append(args, "--skip")
+	}
+	if options.BranchName != "" && !options.Abort && !options.Continue && !options.Skip {
 		args = append(args, options.BranchName)
 	}
 
-	output, _, err := g.executeCommand(args...)
+	output, _, err := g.executeCommandWithOptions(execOptions{env: env, includeStderr: true}, args...)
 	if err != nil {
 		return string(output), fmt.Errorf(
 			"failed to rebase repository: %w",
@@ -0,0 +1,191 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// nonInteractiveEditorEnv makes git skip the editor and keep the prepared
+// commit message, e.g. when squashing or continuing a rebase. Git treats the
+// editor ":" as a no-op on every platform.
+const nonInteractiveEditorEnv = "GIT_EDITOR=:"
+
+// RebaseAction is the command of a single line in an interactive rebase todo list.
+type RebaseAction string
+
+// Defines the actions supported in an interactive rebase todo list.
+const (
+	RebasePick   RebaseAction = "pick"
+	RebaseReword RebaseAction = "reword"
+	RebaseEdit   RebaseAction = "edit"
+	RebaseSquash RebaseAction = "squash"
+	RebaseFixup  RebaseAction = "fixup"
+	RebaseDrop   RebaseAction = "drop"
+)
+
+// RebaseTodoItem is a single commit in an interactive rebase todo list.
+type RebaseTodoItem struct {
+	Action  RebaseAction
+	SHA     string
+	Subject string
+	Message string // The new commit message, used with RebaseReword.
+}
+
+// GetRebaseCommits returns the commits from base up to HEAD as a todo list for
+// an interactive rebase that replays base and everything after it. The items
+// are in the order git applies them, oldest first, and default to RebasePick.
+func (g *GitCommands) GetRebaseCommits(base string) ([]RebaseTodoItem, error) {
+	if base == "" {
+		return nil, fmt.Errorf("base commit is required")
+	}
+
+	// Like the todo list of git, each line of history is listed in one piece.
+	args := []string{"log", "--no-merges", "--reverse", "--topo-order", "--format=%h%x00%s"}
+	if g.hasParent(base) {
+		args = append(args, base+"^..HEAD")
+	} else {
+		args = append(args, "HEAD")
+	}
+
+	output, _, err := g.executeCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits for rebase: %w", err)
+	}
+
+	var items []RebaseTodoItem
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		parts := strings.SplitN(line, "\x00", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		items = append(items, RebaseTodoItem{Action: RebasePick, SHA: parts[0], Subject: parts[1]})
+	}
+	return items, nil
+}
+
+// InteractiveRebase runs `git rebase -i` with the given todo list instead of
+// asking the user to edit it. base is the oldest commit to replay, as passed to
+// GetRebaseCommits, and the todo list is in the order git applies it.
+func (g *GitCommands) InteractiveRebase(base string, todo []RebaseTodoItem) (string, string, error) {
+	if base == "" {
+		return "", "", fmt.Errorf("base commit is required")
+	}
+	if len(todo) == 0 {
+		return "", "", fmt.Errorf("rebase todo list is empty")
+	}
+	for _, item := range todo {
+		if item.Action == RebaseDrop {
+			continue
+		}
+		if item.Action == RebaseSquash || item.Action == RebaseFixup {
+			return "", "", fmt.Errorf("cannot %s %s without a previous commit", item.Action, item.SHA)
+		}
+		break
+	}
+
+	gitDir, err := g.GetGitRepoPath()
+	if err != nil {
+		return "", "", err
+	}
+	// The state of the rebase, which git removes once it is over.
+	stateDir := filepath.Join(gitDir, "rebase-merge")
+
+	tempDir, err := os.MkdirTemp("", "gitx-rebase-")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create rebase todo directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	todoContent, err := buildRebaseTodo(todo, tempDir, stateDir)
+	if err != nil {
+		return "", "", err
+	}
+	todoPath := filepath.Join(tempDir, "git-rebase-todo")
+	if err := os.WriteFile(todoPath, []byte(todoContent), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write rebase todo: %w", err)
+	}
+
+	args := []string{"rebase", "-i"}
+	if g.hasParent(base) {
+		args = append(args, base+"^")
+	} else {
+		args = append(args, "--root")
+	}
+
+	// Git passes the path of its todo file to the sequence editor, which
+	// overwrites it with the prepared list. The reword messages are moved into
+	// the state of the rebase first, so they are kept if the rebase stops
+	// before reaching them and removed along with it.
+	editor := "cp " + shellQuote(todoPath)
+	if slices.ContainsFunc(todo, func(item RebaseTodoItem) bool { return item.Action == RebaseReword }) {
+		editor = fmt.Sprintf("mv %s/%s* %s && %s", shellQuote(tempDir), rebaseMessagePrefix, shellQuote(stateDir), editor)
+	}
+	env := []string{"GIT_SEQUENCE_EDITOR=" + editor, nonInteractiveEditorEnv}
+
+	output, cmdStr, err := g.executeCommandWithOptions(execOptions{env: env}, args...)
+	if err != nil {
+		return string(output), cmdStr, fmt.Errorf("interactive rebase failed: %w", err)
+	}
+	return string(output), cmdStr, nil
+}
+
+// rebaseMessagePrefix starts the names of the files holding reword messages.
+const rebaseMessagePrefix = "gitx-message-"
+
+// buildRebaseTodo renders the todo list in the format git expects. The messages
+// of reworded commits are written to files in dir, and applied by exec lines
+// from stateDir, where they are once the rebase has started.
+func buildRebaseTodo(todo []RebaseTodoItem, dir, stateDir string) (string, error) {
+	var builder strings.Builder
+	for _, item := range todo {
+		action := item.Action
+		if action == "" {
+			action = RebasePick
+		}
+
+		if action == RebaseReword {
+			if strings.TrimSpace(item.Message) == "" {
+				return "", fmt.Errorf("commit message is required to reword %s", item.SHA)
+			}
+			name := rebaseMessagePrefix + item.SHA
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(item.Message), 0644); err != nil {
+				return "", fmt.Errorf("failed to write commit message: %w", err)
+			}
+			fmt.Fprintf(&builder, "pick %s %s\n", item.SHA, item.Subject)
+			fmt.Fprintf(&builder, "exec git commit --amend --only --allow-empty --quiet -F %s\n", shellQuote(filepath.Join(stateDir, name)))
+			continue
+		}
+
+		fmt.Fprintf(&builder, "%s %s %s\n", action, item.SHA, item.Subject)
+	}
+	return builder.String(), nil
+}
+
+// IsRebaseInProgress reports whether a rebase has stopped and is waiting to be
+// continued, skipped or aborted.
+func (g *GitCommands) IsRebaseInProgress() (bool, error) {
+	gitDir, err := g.GetGitRepoPath()
+	if err != nil {
+		return false, err
+	}
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if pathExists(filepath.Join(gitDir, dir)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasParent reports whether the given commit has at least one parent.
+func (g *GitCommands) hasParent(commit string) bool {
+	_, _, err := g.executeCommand("rev-parse", "--verify", "--quiet", commit+"^")
+	return err == nil
+}
+
+// shellQuote quotes s for use in a POSIX shell command line.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
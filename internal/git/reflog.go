@@ -0,0 +1,207 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ReflogEntry is a single movement of HEAD recorded in the reflog.
+type ReflogEntry struct {
+	SHA      string // The abbreviated hash HEAD pointed to after the movement.
+	Selector string // The reflog selector, e.g. "HEAD@{2}".
+	Action   string // The command that moved HEAD, e.g. "commit (amend)" or "checkout".
+	Message  string // The rest of the reflog message.
+}
+
+// Subject returns the full reflog message of the entry, as shown by `git reflog`.
+func (e ReflogEntry) Subject() string {
+	if e.Message == "" {
+		return e.Action
+	}
+	return e.Action + ": " + e.Message
+}
+
+// GetReflog returns the movements of HEAD, the most recent first.
+func (g *GitCommands) GetReflog() ([]ReflogEntry, error) {
+	output, _, err := g.executeCommand("reflog", "show", "--format=%h%x00%gd%x00%gs", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("git reflog failed: %w", err)
+	}
+	return parseReflog(output), nil
+}
+
+// parseReflog parses the NUL-separated output of GetReflog.
+func parseReflog(output string) []ReflogEntry {
+	var entries []ReflogEntry
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		action, message, _ := strings.Cut(fields[2], ": ")
+		entries = append(entries, ReflogEntry{
+			SHA:      fields[0],
+			Selector: fields[1],
+			Action:   action,
+			Message:  message,
+		})
+	}
+	return entries
+}
+
+// Reflog actions recorded for undo and redo, so that undoing twice steps
+// further back instead of undoing the undo.
+const (
+	undoReflogAction = "[gitx undo]"
+	redoReflogAction = "[gitx redo]"
+)
+
+// HeadMoveKind is the way HEAD is moved to undo or redo an action.
+type HeadMoveKind int
+
+// Defines the ways HEAD can be moved.
+const (
+	HeadMoveReset       HeadMoveKind = iota // Hard reset the current branch to Target.
+	HeadMoveCheckout                        // Check out Target, a branch or a commit.
+	HeadMoveAbortRebase                     // Abort the rebase in progress.
+)
+
+// HeadMove describes how to undo or redo an action recorded in the reflog.
+type HeadMove struct {
+	Kind   HeadMoveKind
+	Target string
+	Action string // The reflog message of the action being undone or redone.
+}
+
+// DiscardsChanges reports whether the move resets the working tree, discarding
+// uncommitted changes.
+func (m HeadMove) DiscardsChanges() bool {
+	return m.Kind != HeadMoveCheckout
+}
+
+var (
+	reflogCheckoutRegex    = regexp.MustCompile(`^checkout: moving from (\S+) to (\S+)`)
+	reflogCommitRegex      = regexp.MustCompile(`^(commit|reset: moving to|pull|merge|cherry-pick|revert)`)
+	reflogRebaseStartRegex = regexp.MustCompile(`^rebase (-i )?\(start\)`)
+	reflogRebaseEndRegex   = regexp.MustCompile(`^rebase (-i )?\((finish|abort)\)`)
+)
+
+// reflogAction is a user action found in the reflog, with the states of HEAD
+// before and after it.
+type reflogAction struct {
+	kind    HeadMoveKind
+	from    string
+	to      string
+	subject string
+}
+
+// walkReflogActions calls visit for each user action in the reflog, the most
+// recent first, together with the number of undos not yet redone at that
+// point. Walking stops when visit returns true. A completed rebase counts as a
+// single action, and undo and redo entries are not actions themselves.
+func walkReflogActions(entries []ReflogEntry, visit func(undone int, action reflogAction) bool) {
+	undone := 0
+	rebaseEnd := ""
+	for i, entry := range entries {
+		previous := ""
+		if i+1 < len(entries) {
+			previous = entries[i+1].SHA
+		}
+		subject := entry.Subject()
+
+		var action *reflogAction
+		switch {
+		case rebaseEnd != "":
+			if reflogRebaseStartRegex.MatchString(subject) {
+				action = &reflogAction{kind: HeadMoveReset, from: previous, to: rebaseEnd, subject: "rebase"}
+				rebaseEnd = ""
+			}
+		case strings.HasPrefix(subject, undoReflogAction):
+			undone++
+		case strings.HasPrefix(subject, redoReflogAction):
+			undone--
+		case reflogRebaseEndRegex.MatchString(subject):
+			rebaseEnd = entry.SHA
+		case reflogCheckoutRegex.MatchString(subject):
+			match := reflogCheckoutRegex.FindStringSubmatch(subject)
+			action = &reflogAction{kind: HeadMoveCheckout, from: match[1], to: match[2], subject: subject}
+		case reflogCommitRegex.MatchString(subject):
+			action = &reflogAction{kind: HeadMoveReset, from: previous, to: entry.SHA, subject: subject}
+		case reflogRebaseStartRegex.MatchString(subject):
+			// A rebase that has not finished yet is still in progress.
+			action = &reflogAction{kind: HeadMoveAbortRebase, from: previous, subject: "rebase in progress"}
+		}
+
+		if action == nil {
+			continue
+		}
+		if action.kind != HeadMoveAbortRebase && (action.from == "" || action.from == action.to) {
+			continue // Nothing to go back to, e.g. the initial commit.
+		}
+		if visit(undone, *action) {
+			return
+		}
+		undone--
+	}
+}
+
+// FindUndo returns how to undo the most recent action in the reflog that has
+// not been undone yet, or nil if there is nothing to undo.
+func FindUndo(entries []ReflogEntry) *HeadMove {
+	var move *HeadMove
+	walkReflogActions(entries, func(undone int, action reflogAction) bool {
+		if undone > 0 {
+			return false
+		}
+		move = &HeadMove{Kind: action.kind, Target: action.from, Action: action.subject}
+		return true
+	})
+	return move
+}
+
+// FindRedo returns how to redo the most recently undone action, or nil if
+// there is nothing to redo.
+func FindRedo(entries []ReflogEntry) *HeadMove {
+	var move *HeadMove
+	walkReflogActions(entries, func(undone int, action reflogAction) bool {
+		if undone <= 0 {
+			return true // Nothing has been undone since the last action.
+		}
+		if undone > 1 {
+			return false
+		}
+		if action.kind != HeadMoveAbortRebase {
+			move = &HeadMove{Kind: action.kind, Target: action.to, Action: action.subject}
+		}
+		return true
+	})
+	return move
+}
+
+// UndoHeadMove undoes or redoes an action by moving HEAD as described by move.
+// The movement is recorded in the reflog so that it is taken into account by
+// later calls to FindUndo and FindRedo.
+func (g *GitCommands) UndoHeadMove(move HeadMove, redo bool) (string, string, error) {
+	verb, action := "undo", undoReflogAction
+	if redo {
+		verb, action = "redo", redoReflogAction
+	}
+	options := execOptions{env: []string{"GIT_REFLOG_ACTION=" + action}}
+
+	var args []string
+	switch move.Kind {
+	case HeadMoveReset:
+		args = []string{"reset", "--hard", move.Target}
+	case HeadMoveCheckout:
+		args = []string{"checkout", move.Target}
+	case HeadMoveAbortRebase:
+		args = []string{"rebase", "--abort"}
+	}
+
+	output, cmdStr, err := g.executeCommandWithOptions(options, args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to %s %q: %w", verb, move.Action, err)
+	}
+	return output, cmdStr, nil
+}
@@ -2,19 +2,25 @@ package git
 
 import (
 	"fmt"
+	"io"
+	"strings"
 )
 
 // RemoteOptions specifies the options for managing remotes.
 type RemoteOptions struct {
 	Add     bool
 	Remove  bool
+	Rename  bool
+	SetURL  bool
+	Push    bool // With SetURL, changes the push URL instead of the fetch URL.
 	Name    string
+	NewName string
 	URL     string
 	Verbose bool
 }
 
 // ManageRemote manages the set of repositories ("remotes") whose branches you track.
-func (g *GitCommands) ManageRemote(options RemoteOptions) (string, error) {
+func (g *GitCommands) ManageRemote(options RemoteOptions) (string, string, error) {
 	args := []string{"remote"}
 
 	if options.Verbose {
@@ -23,48 +29,176 @@ func (g *GitCommands) ManageRemote(options RemoteOptions) (string, error) {
 
 	if options.Add {
 		if options.Name == "" || options.URL == "" {
-			return "", fmt.Errorf("remote name and URL are required for adding")
+			return "", "", fmt.Errorf("remote name and URL are required for adding")
 		}
 		args = append(args, "add", options.Name, options.URL)
 	} else if options.Remove {
 		if options.Name == "" {
-			return "", fmt.Errorf("remote name is required for removal")
+			return "", "", fmt.Errorf("remote name is required for removal")
 		}
 		args = append(args, "remove", options.Name)
+	} else if options.Rename {
+		if options.Name == "" || options.NewName == "" {
+			return "", "", fmt.Errorf("both old and new remote names are required")
+		}
+		args = append(args, "rename", options.Name, options.NewName)
+	} else if options.SetURL {
+		if options.Name == "" || options.URL == "" {
+			return "", "", fmt.Errorf("remote name and URL are required for changing the URL")
+		}
+		args = append(args, "set-url")
+		if options.Push {
+			args = append(args, "--push")
+		}
+		args = append(args, options.Name, options.URL)
 	}
 
-	output, _, err := g.executeCommand(args...)
+	output, cmdStr, err := g.executeCommand(args...)
 	if err != nil {
-		return string(output), fmt.Errorf(
+		return string(output), cmdStr, fmt.Errorf(
 			"failed to manage git remote: %w",
 			err,
 		)
 	}
 
-	return string(output), nil
+	return string(output), cmdStr, nil
+}
+
+// Remote represents a configured remote repository.
+type Remote struct {
+	Name     string
+	FetchURL string
+	PushURL  string
+	Branches []*RemoteBranch // Only filled in when requested, see GetRemoteBranches.
+}
+
+// RemoteBranch represents a remote-tracking branch, e.g. "origin/main".
+type RemoteBranch struct {
+	Remote     string
+	Name       string // The name of the branch on the remote, e.g. "main".
+	LastCommit string
+}
+
+// FullName returns the name of the remote-tracking branch, e.g. "origin/main".
+func (b *RemoteBranch) FullName() string {
+	return b.Remote + "/" + b.Name
+}
+
+// GetRemotes lists the configured remotes with their fetch and push URLs.
+func (g *GitCommands) GetRemotes() ([]*Remote, error) {
+	output, _, err := g.ManageRemote(RemoteOptions{Verbose: true})
+	if err != nil {
+		return nil, err
+	}
+	return parseRemotes(output), nil
+}
+
+// parseRemotes parses the output of `git remote -v`, which lists every remote
+// twice: "origin\t<url> (fetch)" and "origin\t<url> (push)".
+func parseRemotes(output string) []*Remote {
+	var remotes []*Remote
+	byName := make(map[string]*Remote)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		name, rest, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		remote, ok := byName[name]
+		if !ok {
+			remote = &Remote{Name: name}
+			byName[name] = remote
+			remotes = append(remotes, remote)
+		}
+		switch {
+		case strings.HasSuffix(rest, " (fetch)"):
+			remote.FetchURL = strings.TrimSuffix(rest, " (fetch)")
+		case strings.HasSuffix(rest, " (push)"):
+			remote.PushURL = strings.TrimSuffix(rest, " (push)")
+		}
+	}
+	return remotes
+}
+
+// GetRemoteBranches lists the remote-tracking branches of a remote, the most
+// recently updated first.
+func (g *GitCommands) GetRemoteBranches(remote string) ([]*RemoteBranch, error) {
+	if remote == "" {
+		return nil, fmt.Errorf("remote name is required")
+	}
+	format := "%(committerdate:relative)\t%(refname)\t%(symref)"
+	args := []string{"for-each-ref", "--sort=-committerdate", fmt.Sprintf("--format=%s", format), "refs/remotes/" + remote + "/"}
+
+	output, _, err := g.executeCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches of remote %s: %w", remote, err)
+	}
+
+	prefix := "refs/remotes/" + remote + "/"
+	var branches []*RemoteBranch
+	// The symref column is usually empty, so only the line breaks are trimmed.
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 || parts[2] != "" {
+			continue // Skip symbolic refs such as origin/HEAD.
+		}
+		branches = append(branches, &RemoteBranch{
+			Remote:     remote,
+			Name:       strings.TrimPrefix(parts[1], prefix),
+			LastCommit: formatRelativeDate(parts[0]),
+		})
+	}
+	return branches, nil
+}
+
+// CheckoutRemoteBranch creates a local branch that tracks a remote-tracking
+// branch and checks it out.
+func (g *GitCommands) CheckoutRemoteBranch(branch *RemoteBranch, localName string) (string, string, error) {
+	if localName == "" {
+		localName = branch.Name
+	}
+	args := []string{"checkout", "-b", localName, "--track", branch.FullName()}
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to check out %s: %w", branch.FullName(), err)
+	}
+	return output, cmdStr, nil
+}
+
+// FetchOptions specifies the options for the git fetch command.
+type FetchOptions struct {
+	Remote string
+	Branch string
+	All    bool
+	// Progress receives the progress output of git while it runs.
+	Progress io.Writer
 }
 
 // Fetch downloads objects and refs from another repository.
-func (g *GitCommands) Fetch(remote string, branch string) (string, error) {
+func (g *GitCommands) Fetch(options FetchOptions) (string, string, error) {
 	args := []string{"fetch"}
 
-	if remote != "" {
-		args = append(args, remote)
+	if options.All {
+		args = append(args, "--all")
 	}
 
-	if branch != "" {
-		args = append(args, branch)
+	if options.Remote != "" {
+		args = append(args, options.Remote)
 	}
 
-	output, _, err := g.executeCommand(args...)
+	if options.Branch != "" {
+		args = append(args, options.Branch)
+	}
+
+	output, cmdStr, err := g.executeRemoteCommand(options.Progress, args...)
 	if err != nil {
-		return string(output), fmt.Errorf(
+		return string(output), cmdStr, fmt.Errorf(
 			"failed to fetch from remote: %w",
 			err,
 		)
 	}
 
-	return string(output), nil
+	return string(output), cmdStr, nil
 }
 
 // PullOptions specifies the options for the git pull command.
@@ -72,10 +206,12 @@ type PullOptions struct {
 	Remote string
 	Branch string
 	Rebase bool
+	// Progress receives the progress output of git while it runs.
+	Progress io.Writer
 }
 
 // Pull fetches from and integrates with another repository or a local branch.
-func (g *GitCommands) Pull(options PullOptions) (string, error) {
+func (g *GitCommands) Pull(options PullOptions) (string, string, error) {
 	args := []string{"pull"}
 
 	if options.Rebase {
@@ -90,28 +226,46 @@ func (g *GitCommands) Pull(options PullOptions) (string, error) {
 		args = append(args, options.Branch)
 	}
 
-	output, _, err := g.executeCommand(args...)
+	output, cmdStr, err := g.executeRemoteCommand(options.Progress, args...)
 	if err != nil {
-		return string(output), fmt.Errorf(
+		return string(output), cmdStr, fmt.Errorf(
 			"failed to pull repository: %w",
 			err,
 		)
 	}
 
-	return string(output), nil
+	return string(output), cmdStr, nil
+}
+
+// executeRemoteCommand runs a command that talks to a remote. If progress is
+// set, git is asked to report its progress, which is written to progress while
+// the command runs. Git never prompts for credentials on the terminal, as the
+// TUI owns it; a credential helper or an SSH agent must be set up instead.
+func (g *GitCommands) executeRemoteCommand(progress io.Writer, args ...string) (string, string, error) {
+	options := execOptions{env: []string{"GIT_TERMINAL_PROMPT=0"}, remote: true}
+	if progress != nil {
+		// The flag must follow the subcommand, e.g. `git push --progress`.
+		args = append([]string{args[0], "--progress"}, args[1:]...)
+		options.output = progress
+	}
+	return g.executeCommandWithOptions(options, args...)
 }
 
 // PushOptions specifies the options for the git push command.
 type PushOptions struct {
 	Remote      string
 	Branch      string
+	Tag         string // Pushes a single tag instead of a branch.
+	Delete      bool   // Deletes Branch or Tag on the remote instead of pushing it.
 	Force       bool
 	SetUpstream bool
 	Tags        bool
+	// Progress receives the progress output of git while it runs.
+	Progress io.Writer
 }
 
 // Push updates remote refs along with associated objects.
-func (g *GitCommands) Push(options PushOptions) (string, error) {
+func (g *GitCommands) Push(options PushOptions) (string, string, error) {
 	args := []string{"push"}
 
 	if options.Force {
@@ -126,6 +280,13 @@ func (g *GitCommands) Push(options PushOptions) (string, error) {
 		args = append(args, "--tags")
 	}
 
+	if options.Delete {
+		if options.Remote == "" || (options.Branch == "" && options.Tag == "") {
+			return "", "", fmt.Errorf("remote and branch or tag are required for deletion")
+		}
+		args = append(args, "--delete")
+	}
+
 	if options.Remote != "" {
 		args = append(args, options.Remote)
 	}
@@ -134,13 +295,17 @@ func (g *GitCommands) Push(options PushOptions) (string, error) {
 		args = append(args, options.Branch)
 	}
 
-	output, _, err := g.executeCommand(args...)
+	if options.Tag != "" {
+		args = append(args, "refs/tags/"+options.Tag)
+	}
+
+	output, cmdStr, err := g.executeRemoteCommand(options.Progress, args...)
 	if err != nil {
-		return string(output), fmt.Errorf(
+		return string(output), cmdStr, fmt.Errorf(
 			"failed to push to remote: %w",
 			err,
 		)
 	}
 
-	return string(output), nil
+	return string(output), cmdStr, nil
 }
@@ -32,8 +32,27 @@ func (g *GitCommands) GetRepoInfo() (repoName string, branchName string, err err
 	return repoName, branchName, nil
 }
 
+// GetRepoRoot returns the absolute path of the root of the work tree.
+func (g *GitCommands) GetRepoRoot() (string, error) {
+	root, _, err := g.executeCommand("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root path: %w", err)
+	}
+	return strings.TrimSpace(root), nil
+}
+
+// repoRootPath returns the location of a path relative to the root of the
+// work tree.
+func (g *GitCommands) repoRootPath(path string) (string, error) {
+	root, err := g.GetRepoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, path), nil
+}
+
 func (g *GitCommands) GetGitRepoPath() (repoPath string, err error) {
-	repoPath, _, err = g.executeCommand("rev-parse", "--git-dir")
+	repoPath, _, err = g.executeCommand("rev-parse", "--absolute-git-dir")
 	if err != nil {
 		return "", fmt.Errorf(
 			"failed to get git directory path: %w",
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"strings"
 	"testing"
 )
@@ -24,7 +25,7 @@ func TestGetRepoInfo(t *testing.T) {
 	}
 
 	// Verify with actual git commands
-	expectedBranchBytes, err := ExecCommand("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	expectedBranchBytes, err := ExecCommand(context.Background(), "git", "rev-parse", "--abbrev-ref", "HEAD").Output()
 	if err != nil {
 		t.Fatalf("Failed to get branch name from git: %v", err)
 	}
@@ -98,7 +98,7 @@ func (g *GitCommands) Stash(options StashOptions) (string, string, error) {
 	output, cmdStr, err := g.executeCommand(args...)
 	if err != nil {
 		// The command fails if there's no stash.
-		if strings.Contains(string(output), "No stash entries found") || strings.Contains(string(output), "No stash found") {
+		if ErrorKindOf(err) == ErrorNoStash {
 			return "No stashes found.", cmdStr, nil
 		}
 		return string(output), cmdStr, fmt.Errorf("git stash command failed: %w", err)
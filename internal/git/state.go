@@ -0,0 +1,195 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RepoOperation is a multi-step operation that is in progress in the repository.
+type RepoOperation string
+
+// Defines the operations that can be in progress in a repository.
+const (
+	OperationNone       RepoOperation = ""
+	OperationMerge      RepoOperation = "merge"
+	OperationRebase     RepoOperation = "rebase"
+	OperationCherryPick RepoOperation = "cherry-pick"
+	OperationRevert     RepoOperation = "revert"
+	OperationBisect     RepoOperation = "bisect"
+)
+
+// CanContinue reports whether the operation can be continued after resolving a stop.
+func (op RepoOperation) CanContinue() bool {
+	return op != OperationNone && op != OperationBisect
+}
+
+// CanSkip reports whether the current step of the operation can be skipped.
+func (op RepoOperation) CanSkip() bool {
+	return op != OperationNone && op != OperationMerge
+}
+
+// CanAbort reports whether the operation can be aborted.
+func (op RepoOperation) CanAbort() bool {
+	return op != OperationNone
+}
+
+// RepoState describes the state of HEAD and any operation in progress.
+type RepoState struct {
+	Operation RepoOperation
+	Branch    string       // The checked out branch, or the branch being rebased.
+	Tracking  Tracking     // How the checked out branch relates to its upstream.
+	Detached  bool         // Whether HEAD points directly at a commit.
+	Head      string       // The abbreviated commit HEAD points at.
+	Target    string       // The abbreviated commit being merged, picked, reverted or rebased onto.
+	Step      int          // The current step of a rebase, starting at 1.
+	Total     int          // The number of steps of a rebase.
+	Bisect    *BisectState // The progress of a bisect, if one is in progress.
+}
+
+// GetRepoState detects the operation in progress from the files git keeps in
+// its directory, e.g. MERGE_HEAD during a merge.
+func (g *GitCommands) GetRepoState() (*RepoState, error) {
+	gitDir, err := g.GetGitRepoPath()
+	if err != nil {
+		return nil, err
+	}
+	state := &RepoState{}
+
+	if head, _, err := g.executeCommand("rev-parse", "--short", "HEAD"); err == nil {
+		state.Head = strings.TrimSpace(head)
+	}
+	if branch, _, err := g.executeCommand("symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		state.Branch = strings.TrimSpace(branch)
+		if state.Tracking, err = g.getTracking(state.Branch); err != nil {
+			return nil, err
+		}
+	} else {
+		state.Detached = true
+	}
+
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		rebaseDir := filepath.Join(gitDir, dir)
+		if !pathExists(rebaseDir) {
+			continue
+		}
+		state.Operation = OperationRebase
+		if headName := readGitFile(filepath.Join(rebaseDir, "head-name")); headName != "" {
+			state.Branch = strings.TrimPrefix(headName, "refs/heads/")
+		}
+		state.Target = g.abbreviate(readGitFile(filepath.Join(rebaseDir, "onto")))
+		if dir == "rebase-merge" {
+			state.Step, _ = strconv.Atoi(readGitFile(filepath.Join(rebaseDir, "msgnum")))
+			state.Total, _ = strconv.Atoi(readGitFile(filepath.Join(rebaseDir, "end")))
+		} else {
+			state.Step, _ = strconv.Atoi(readGitFile(filepath.Join(rebaseDir, "next")))
+			state.Total, _ = strconv.Atoi(readGitFile(filepath.Join(rebaseDir, "last")))
+		}
+		return state, nil
+	}
+
+	heads := []struct {
+		file      string
+		operation RepoOperation
+	}{
+		{"MERGE_HEAD", OperationMerge},
+		{"CHERRY_PICK_HEAD", OperationCherryPick},
+		{"REVERT_HEAD", OperationRevert},
+	}
+	for _, head := range heads {
+		if sha := readGitFile(filepath.Join(gitDir, head.file)); sha != "" {
+			state.Operation = head.operation
+			state.Target = g.abbreviate(strings.Fields(sha)[0])
+			return state, nil
+		}
+	}
+
+	if pathExists(filepath.Join(gitDir, "BISECT_LOG")) {
+		state.Operation = OperationBisect
+		if state.Bisect, err = g.GetBisectState(); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// ContinueOperation continues the operation in progress after its current stop
+// has been resolved, keeping the prepared commit message.
+func (g *GitCommands) ContinueOperation(op RepoOperation) (string, string, error) {
+	if !op.CanContinue() {
+		return "", "", fmt.Errorf("cannot continue %s", operationName(op))
+	}
+	return g.runOperationCommand(execOptions{env: []string{nonInteractiveEditorEnv}}, string(op), "--continue")
+}
+
+// SkipOperation skips the current step of the operation in progress.
+func (g *GitCommands) SkipOperation(op RepoOperation) (string, string, error) {
+	if !op.CanSkip() {
+		return "", "", fmt.Errorf("cannot skip %s", operationName(op))
+	}
+	if op == OperationBisect {
+		return g.runOperationCommand(execOptions{}, "bisect", "skip")
+	}
+	return g.runOperationCommand(execOptions{}, string(op), "--skip")
+}
+
+// AbortOperation aborts the operation in progress and restores the state from
+// before it started.
+func (g *GitCommands) AbortOperation(op RepoOperation) (string, string, error) {
+	if !op.CanAbort() {
+		return "", "", fmt.Errorf("cannot abort %s", operationName(op))
+	}
+	if op == OperationBisect {
+		return g.runOperationCommand(execOptions{}, "bisect", "reset")
+	}
+	return g.runOperationCommand(execOptions{}, string(op), "--abort")
+}
+
+// runOperationCommand runs a command that changes the state of an operation.
+func (g *GitCommands) runOperationCommand(options execOptions, args ...string) (string, string, error) {
+	output, cmdStr, err := g.executeCommandWithOptions(options, args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return output, cmdStr, nil
+}
+
+// operationName returns a description of op for error messages.
+func operationName(op RepoOperation) string {
+	if op == OperationNone {
+		return "without an operation in progress"
+	}
+	return string(op)
+}
+
+// abbreviate returns the abbreviated form of a commit hash, or the hash itself
+// if it cannot be resolved.
+func (g *GitCommands) abbreviate(sha string) string {
+	if sha == "" {
+		return ""
+	}
+	short, _, err := g.executeCommand("rev-parse", "--short", sha)
+	if err != nil {
+		return sha
+	}
+	return strings.TrimSpace(short)
+}
+
+// readGitFile returns the trimmed content of a file in the git directory, or an
+// empty string if it does not exist.
+func readGitFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// pathExists reports whether a file or directory exists at path.
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
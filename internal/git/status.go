@@ -2,6 +2,7 @@ package git
 
 import (
 	"fmt"
+	"strings"
 )
 
 // StatusOptions specifies arguments for git status command.
@@ -22,3 +23,259 @@ func (g *GitCommands) GetStatus(options StatusOptions) (string, error) {
 	}
 	return string(output), nil
 }
+
+// FileState is the single-letter state of a file in the index or the work tree,
+// as reported by `git status`.
+type FileState byte
+
+// Defines the file states reported by `git status --porcelain=v2`.
+const (
+	StateUnmodified  FileState = '.'
+	StateModified    FileState = 'M'
+	StateTypeChanged FileState = 'T'
+	StateAdded       FileState = 'A'
+	StateDeleted     FileState = 'D'
+	StateRenamed     FileState = 'R'
+	StateCopied      FileState = 'C'
+	StateUnmerged    FileState = 'U'
+	StateUntracked   FileState = '?'
+	StateIgnored     FileState = '!'
+)
+
+// ConflictType describes how the two sides of a merge disagree about an unmerged file.
+type ConflictType int
+
+// Defines the conflict types of unmerged files.
+const (
+	ConflictNone          ConflictType = iota
+	ConflictBothDeleted                // DD
+	ConflictAddedByUs                  // AU
+	ConflictDeletedByThem              // UD
+	ConflictAddedByThem                // UA
+	ConflictDeletedByUs                // DU
+	ConflictBothAdded                  // AA
+	ConflictBothModified               // UU
+)
+
+// conflictTypes maps the XY code of an unmerged entry to its ConflictType.
+var conflictTypes = map[string]ConflictType{
+	"DD": ConflictBothDeleted,
+	"AU": ConflictAddedByUs,
+	"UD": ConflictDeletedByThem,
+	"UA": ConflictAddedByThem,
+	"DU": ConflictDeletedByUs,
+	"AA": ConflictBothAdded,
+	"UU": ConflictBothModified,
+}
+
+// SubmoduleStatus holds the submodule flags of a status entry.
+type SubmoduleStatus struct {
+	IsSubmodule   bool
+	CommitChanged bool // The checked out commit differs from the recorded one.
+	HasModified   bool // The submodule has tracked changes.
+	HasUntracked  bool // The submodule has untracked files.
+}
+
+// Summary describes the changes in the submodule the way `git status` does,
+// e.g. "new commits, modified content". It is empty if there are none.
+func (s SubmoduleStatus) Summary() string {
+	var changes []string
+	if s.CommitChanged {
+		changes = append(changes, "new commits")
+	}
+	if s.HasModified {
+		changes = append(changes, "modified content")
+	}
+	if s.HasUntracked {
+		changes = append(changes, "untracked content")
+	}
+	return strings.Join(changes, ", ")
+}
+
+// FileStatus represents a single entry of `git status`.
+type FileStatus struct {
+	Path      string
+	OrigPath  string // The source path of a rename or copy.
+	Index     FileState
+	Worktree  FileState
+	Submodule SubmoduleStatus
+	Conflict  ConflictType
+}
+
+// Code returns the two-letter status code of the entry in the format of
+// `git status --short`, e.g. "M ", " M", "??" or "UU".
+func (f FileStatus) Code() string {
+	toShort := func(s FileState) byte {
+		if s == StateUnmodified || s == 0 {
+			return ' '
+		}
+		return byte(s)
+	}
+	return string([]byte{toShort(f.Index), toShort(f.Worktree)})
+}
+
+// IsUntracked reports whether the file is not tracked by git.
+func (f FileStatus) IsUntracked() bool {
+	return f.Index == StateUntracked
+}
+
+// IsRenamed reports whether the file was renamed or copied in the index.
+func (f FileStatus) IsRenamed() bool {
+	return f.Index == StateRenamed || f.Index == StateCopied
+}
+
+// IsConflicted reports whether the file is unmerged.
+func (f FileStatus) IsConflicted() bool {
+	return f.Conflict != ConflictNone
+}
+
+// HasStagedChanges reports whether the index differs from HEAD for this file.
+func (f FileStatus) HasStagedChanges() bool {
+	return !f.IsUntracked() && f.Index != StateIgnored && f.Index != StateUnmodified
+}
+
+// HasUnstagedChanges reports whether the work tree differs from the index for
+// this file, including untracked files.
+func (f FileStatus) HasUnstagedChanges() bool {
+	return f.Worktree != StateUnmodified && f.Worktree != StateIgnored
+}
+
+// BranchStatus holds the branch information reported by `git status --branch`.
+type BranchStatus struct {
+	OID      string // The commit HEAD points to, or "(initial)" before the first commit.
+	Head     string // The current branch, or "(detached)".
+	Upstream string // The upstream branch, if one is configured.
+	Ahead    int
+	Behind   int
+}
+
+// IsDetached reports whether HEAD is detached.
+func (b BranchStatus) IsDetached() bool {
+	return b.Head == "(detached)"
+}
+
+// RepoStatus is the parsed output of `git status --porcelain=v2 --branch`.
+type RepoStatus struct {
+	Branch BranchStatus
+	Files  []FileStatus
+}
+
+// HasTrackedChanges reports whether any tracked file has staged or unstaged
+// changes, which a hard reset would discard.
+func (s *RepoStatus) HasTrackedChanges() bool {
+	for _, f := range s.Files {
+		if !f.IsUntracked() && f.Index != StateIgnored && (f.HasStagedChanges() || f.HasUnstagedChanges()) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRepoStatus runs `git status --porcelain=v2 -z --branch` and returns the
+// parsed branch information and file entries.
+func (g *GitCommands) GetRepoStatus() (*RepoStatus, error) {
+	args := []string{"status", "--porcelain=v2", "-z", "--branch"}
+
+	output, _, err := g.executeCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	return ParseStatus(output), nil
+}
+
+// ParseStatus parses the NUL-separated output of
+// `git status --porcelain=v2 -z --branch` into a RepoStatus.
+func ParseStatus(output string) *RepoStatus {
+	status := &RepoStatus{}
+	records := strings.Split(output, "\x00")
+
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 2 {
+			continue
+		}
+
+		switch record[0] {
+		case '#':
+			parseBranchHeader(&status.Branch, record)
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) == 9 {
+				status.Files = append(status.Files, newFileStatus(fields[1], fields[2], fields[8]))
+			}
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, followed by
+			// the original path as a separate record.
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) == 10 {
+				file := newFileStatus(fields[1], fields[2], fields[9])
+				if i+1 < len(records) {
+					i++
+					file.OrigPath = records[i]
+				}
+				status.Files = append(status.Files, file)
+			}
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) == 11 {
+				file := newFileStatus(fields[1], fields[2], fields[10])
+				file.Conflict = conflictTypes[fields[1]]
+				status.Files = append(status.Files, file)
+			}
+		case '?', '!':
+			state := FileState(record[0])
+			status.Files = append(status.Files, FileStatus{
+				Path:     record[2:],
+				Index:    state,
+				Worktree: state,
+			})
+		}
+	}
+	return status
+}
+
+// newFileStatus creates a FileStatus from the XY, submodule and path fields of
+// a porcelain v2 entry.
+func newFileStatus(xy, sub, path string) FileStatus {
+	file := FileStatus{Path: path}
+	if len(xy) == 2 {
+		file.Index = FileState(xy[0])
+		file.Worktree = FileState(xy[1])
+	}
+	// <sub> is "N..." for normal files, or "S<c><m><u>" for submodules.
+	if len(sub) == 4 && sub[0] == 'S' {
+		file.Submodule = SubmoduleStatus{
+			IsSubmodule:   true,
+			CommitChanged: sub[1] == 'C',
+			HasModified:   sub[2] == 'M',
+			HasUntracked:  sub[3] == 'U',
+		}
+	}
+	return file
+}
+
+// parseBranchHeader fills in the branch information from a "# branch.*" header.
+func parseBranchHeader(branch *BranchStatus, header string) {
+	fields := strings.SplitN(header, " ", 3)
+	if len(fields) < 3 {
+		return
+	}
+
+	switch fields[1] {
+	case "branch.oid":
+		branch.OID = fields[2]
+	case "branch.head":
+		branch.Head = fields[2]
+	case "branch.upstream":
+		branch.Upstream = fields[2]
+	case "branch.ab":
+		// "+<ahead> -<behind>"
+		counts := strings.Fields(fields[2])
+		if len(counts) == 2 {
+			branch.Ahead = atoiOr(strings.TrimPrefix(counts[0], "+"), 0)
+			branch.Behind = atoiOr(strings.TrimPrefix(counts[1], "-"), 0)
+		}
+	}
+}
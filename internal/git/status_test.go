@@ -0,0 +1,62 @@
+package git
+
+import "testing"
+
+func TestParseStatus(t *testing.T) {
+	output := "# branch.oid 1234567890abcdef\x00" +
+		"# branch.head main\x00" +
+		"# branch.upstream origin/main\x00" +
+		"# branch.ab +2 -1\x00" +
+		"1 .M N... 100644 100644 100644 abc abc dir/with space.txt\x00" +
+		"2 R. N... 100644 100644 100644 abc abc R100 new -> name.txt\x00old -> name.txt\x00" +
+		"1 .M SC.U 160000 160000 160000 abc abc vendor/lib\x00" +
+		"u UU N... 100644 100644 100644 100644 a b c conflict.txt\x00" +
+		"? ünïcödé.txt\x00"
+
+	status := ParseStatus(output)
+
+	wantBranch := BranchStatus{OID: "1234567890abcdef", Head: "main", Upstream: "origin/main", Ahead: 2, Behind: 1}
+	if status.Branch != wantBranch {
+		t.Errorf("got branch %+v, want %+v", status.Branch, wantBranch)
+	}
+
+	if len(status.Files) != 5 {
+		t.Fatalf("expected 5 entries, got %d: %+v", len(status.Files), status.Files)
+	}
+
+	modified := status.Files[0]
+	if modified.Path != "dir/with space.txt" || modified.Code() != " M" || !modified.HasUnstagedChanges() || modified.HasStagedChanges() {
+		t.Errorf("unexpected modified entry: %+v", modified)
+	}
+
+	renamed := status.Files[1]
+	if renamed.Path != "new -> name.txt" || renamed.OrigPath != "old -> name.txt" || !renamed.IsRenamed() {
+		t.Errorf("unexpected renamed entry: %+v", renamed)
+	}
+
+	submodule := status.Files[2]
+	wantSubmodule := SubmoduleStatus{IsSubmodule: true, CommitChanged: true, HasUntracked: true}
+	if submodule.Submodule != wantSubmodule {
+		t.Errorf("got submodule flags %+v, want %+v", submodule.Submodule, wantSubmodule)
+	}
+
+	conflict := status.Files[3]
+	if !conflict.IsConflicted() || conflict.Conflict != ConflictBothModified || conflict.Code() != "UU" {
+		t.Errorf("unexpected conflicted entry: %+v", conflict)
+	}
+
+	untracked := status.Files[4]
+	if untracked.Path != "ünïcödé.txt" || !untracked.IsUntracked() || untracked.Code() != "??" {
+		t.Errorf("unexpected untracked entry: %+v", untracked)
+	}
+}
+
+func TestParseStatus_DetachedHead(t *testing.T) {
+	status := ParseStatus("# branch.oid 1234567\x00# branch.head (detached)\x00")
+	if !status.Branch.IsDetached() {
+		t.Error("expected detached HEAD")
+	}
+	if len(status.Files) != 0 {
+		t.Errorf("expected no entries, got %d", len(status.Files))
+	}
+}
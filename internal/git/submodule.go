@@ -0,0 +1,202 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Submodule represents a submodule of the repository.
+type Submodule struct {
+	Name          string
+	Path          string // The path of the submodule, relative to the root of the work tree.
+	URL           string
+	RecordedSHA   string // The commit recorded in the index of the superproject.
+	CheckedOutSHA string // The commit checked out in the submodule, empty if it is not initialized.
+	Initialized   bool
+	Conflict      bool // Whether the submodule has merge conflicts in the superproject.
+	Status        SubmoduleStatus
+}
+
+// OutOfDate reports whether the submodule has another commit checked out than
+// the one recorded in the superproject.
+func (s *Submodule) OutOfDate() bool {
+	return s.Initialized && s.CheckedOutSHA != s.RecordedSHA
+}
+
+// GetSubmodules lists the submodules of the repository with the commit
+// recorded for each and the commit checked out in it. Like the paths the other
+// submodule commands take, their paths are relative to the directory the
+// commands run in, so that should be the root of the work tree.
+func (g *GitCommands) GetSubmodules() ([]*Submodule, error) {
+	output, _, err := g.executeCommand("submodule", "status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submodules: %w", err)
+	}
+	submodules := ParseSubmoduleStatus(output)
+	if len(submodules) == 0 {
+		return submodules, nil
+	}
+
+	paths := make([]string, len(submodules))
+	byPath := make(map[string]*Submodule, len(submodules))
+	for i, s := range submodules {
+		paths[i] = s.Path
+		byPath[s.Path] = s
+	}
+
+	output, _, err = g.executeCommand(append([]string{"submodule", "status", "--cached", "--"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submodules: %w", err)
+	}
+	for _, recorded := range ParseSubmoduleStatus(output) {
+		if s, ok := byPath[recorded.Path]; ok {
+			s.RecordedSHA = recorded.CheckedOutSHA
+			if !s.Initialized {
+				s.CheckedOutSHA = ""
+			}
+		}
+	}
+
+	output, _, err = g.executeCommand(append([]string{"status", "--porcelain=v2", "-z", "--"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	for _, file := range ParseStatus(output).Files {
+		if s, ok := byPath[file.Path]; ok {
+			s.Status = file.Submodule
+		}
+	}
+
+	// .gitmodules may be missing or list no submodules, which git reports as an error.
+	output, _, _ = g.executeCommand("config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.(path|url)$`)
+	for name, config := range parseGitmodules(output) {
+		if s, ok := byPath[config.Path]; ok {
+			s.Name = name
+			s.URL = config.URL
+		}
+	}
+	return submodules, nil
+}
+
+// ParseSubmoduleStatus parses the output of `git submodule status`, which
+// prints a line per submodule: a flag, the commit checked out in the submodule
+// (or the recorded commit if it is not initialized), its path and, if it is
+// initialized, the output of `git describe` for the commit in parentheses.
+func ParseSubmoduleStatus(output string) []*Submodule {
+	var submodules []*Submodule
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		sha, path, ok := strings.Cut(line[1:], " ")
+		if !ok {
+			continue
+		}
+		if i := strings.LastIndex(path, " ("); i >= 0 && strings.HasSuffix(path, ")") {
+			path = path[:i]
+		}
+		s := &Submodule{Path: path, CheckedOutSHA: sha, RecordedSHA: sha, Initialized: true}
+		switch line[0] {
+		case '-':
+			s.Initialized = false
+		case '+':
+			s.RecordedSHA = ""
+		case 'U':
+			s.Conflict = true
+		}
+		submodules = append(submodules, s)
+	}
+	return submodules
+}
+
+// gitmodulesEntry holds the settings of a submodule in .gitmodules.
+type gitmodulesEntry struct {
+	Path string
+	URL  string
+}
+
+// parseGitmodules parses the output of `git config --get-regexp` for the path
+// and url settings in .gitmodules, keyed by the name of the submodule.
+func parseGitmodules(output string) map[string]gitmodulesEntry {
+	entries := make(map[string]gitmodulesEntry)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		// The key is submodule.<name>.<setting>, and the name may contain dots.
+		key = strings.TrimPrefix(key, "submodule.")
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			continue
+		}
+		name, setting := key[:i], key[i+1:]
+		entry := entries[name]
+		switch setting {
+		case "path":
+			entry.Path = value
+		case "url":
+			entry.URL = value
+		}
+		entries[name] = entry
+	}
+	return entries
+}
+
+// InitSubmodules registers the submodules at paths, or all of them if paths is
+// empty, in the configuration of the repository.
+func (g *GitCommands) InitSubmodules(paths ...string) (string, string, error) {
+	args := append([]string{"submodule", "init", "--"}, paths...)
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to initialize submodules: %w", err)
+	}
+	return output, cmdStr, nil
+}
+
+// SubmoduleUpdateOptions specifies the options for updating submodules.
+type SubmoduleUpdateOptions struct {
+	Paths     []string // The submodules to update, all of them if empty.
+	Init      bool     // Initializes the submodules that are not initialized yet.
+	Recursive bool     // Updates the submodules of the submodules as well.
+	// Progress receives the progress output of git while it runs.
+	Progress io.Writer
+}
+
+// UpdateSubmodules clones missing submodules and checks out the commits
+// recorded in the superproject in them.
+func (g *GitCommands) UpdateSubmodules(options SubmoduleUpdateOptions) (string, string, error) {
+	args := []string{"submodule", "update"}
+	execOpts := execOptions{env: []string{"GIT_TERMINAL_PROMPT=0"}, remote: true}
+	if options.Progress != nil {
+		args = append(args, "--progress")
+		execOpts.output = options.Progress
+	}
+	if options.Init {
+		args = append(args, "--init")
+	}
+	if options.Recursive {
+		args = append(args, "--recursive")
+	}
+	args = append(append(args, "--"), options.Paths...)
+
+	// Unlike executeRemoteCommand, the --progress flag must follow "update".
+	output, cmdStr, err := g.executeCommandWithOptions(execOpts, args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to update submodules: %w", err)
+	}
+	return output, cmdStr, nil
+}
+
+// SyncSubmodules copies the URLs of the submodules at paths, or of all of them
+// if paths is empty, from .gitmodules to the configuration of the repository,
+// e.g. after a submodule has moved to another remote.
+func (g *GitCommands) SyncSubmodules(paths ...string) (string, string, error) {
+	args := append([]string{"submodule", "sync", "--recursive", "--"}, paths...)
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to sync submodules: %w", err)
+	}
+	return output, cmdStr, nil
+}
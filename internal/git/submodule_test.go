@@ -0,0 +1,125 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const sampleSubmoduleStatus = " 1111111111111111111111111111111111111111 libs/core (v1.0.0)\n" +
+	"+2222222222222222222222222222222222222222 libs/ui (heads/main)\n" +
+	"-3333333333333333333333333333333333333333 vendor/my lib\n" +
+	"U0000000000000000000000000000000000000000 themes\n"
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	submodules := ParseSubmoduleStatus(sampleSubmoduleStatus)
+	if len(submodules) != 4 {
+		t.Fatalf("expected 4 submodules, got %d: %+v", len(submodules), submodules)
+	}
+
+	core, ui, lib, themes := submodules[0], submodules[1], submodules[2], submodules[3]
+	if core.Path != "libs/core" || !core.Initialized || core.OutOfDate() || core.CheckedOutSHA != "1111111111111111111111111111111111111111" {
+		t.Errorf("unexpected up-to-date submodule: %+v", core)
+	}
+	if ui.Path != "libs/ui" || !ui.OutOfDate() {
+		t.Errorf("unexpected out-of-date submodule: %+v", ui)
+	}
+	if lib.Path != "vendor/my lib" || lib.Initialized || lib.OutOfDate() {
+		t.Errorf("unexpected uninitialized submodule: %+v", lib)
+	}
+	if themes.Path != "themes" || !themes.Conflict {
+		t.Errorf("unexpected conflicted submodule: %+v", themes)
+	}
+}
+
+func TestParseGitmodules(t *testing.T) {
+	entries := parseGitmodules("submodule.core.path libs/core\n" +
+		"submodule.core.url https://example.com/core.git\n" +
+		"submodule.ui.v2.path libs/ui\n")
+	if core := entries["core"]; core.Path != "libs/core" || core.URL != "https://example.com/core.git" {
+		t.Errorf("unexpected core entry: %+v", core)
+	}
+	if ui := entries["ui.v2"]; ui.Path != "libs/ui" {
+		t.Errorf("unexpected entry for a name with dots: %+v", ui)
+	}
+}
+
+func TestGitCommands_Submodules(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+	libPath, cleanupLib := setupRemoteRepo(t)
+	defer cleanupLib()
+
+	g := NewGitCommands()
+	if submodules, err := g.GetSubmodules(); err != nil || len(submodules) != 0 {
+		t.Fatalf("expected no submodules, got %+v, %v", submodules, err)
+	}
+
+	// Cloning from a local path is only allowed with protocol.file.allow.
+	cmd := exec.Command("git", "-c", "protocol.file.allow=always", "submodule", "add", libPath, "libs/lib")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to add submodule: %v: %s", err, output)
+	}
+	if _, _, err := g.Commit(CommitOptions{Message: "Add submodule"}); err != nil {
+		t.Fatalf("failed to commit submodule: %v", err)
+	}
+
+	submodules, err := g.GetSubmodules()
+	if err != nil {
+		t.Fatalf("GetSubmodules() failed: %v", err)
+	}
+	if len(submodules) != 1 {
+		t.Fatalf("expected 1 submodule, got %+v", submodules)
+	}
+	lib := submodules[0]
+	if lib.Path != "libs/lib" || lib.Name != "libs/lib" || lib.URL != libPath || !lib.Initialized || lib.OutOfDate() {
+		t.Errorf("unexpected submodule: %+v", lib)
+	}
+
+	// Changes inside the submodule show up in its status.
+	if err := os.WriteFile(filepath.Join("libs", "lib", "testfile.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to modify submodule: %v", err)
+	}
+	if submodules, _ = g.GetSubmodules(); !submodules[0].Status.HasModified || submodules[0].Status.Summary() != "modified content" {
+		t.Errorf("expected a modified submodule, got %+v", submodules[0])
+	}
+	sub := NewGitCommands().WithRepoPath(filepath.Join("libs", "lib"))
+	if err := runGitConfig(filepath.Join("libs", "lib")); err != nil {
+		t.Fatalf("failed to set git config in submodule: %v", err)
+	}
+	if _, _, err := sub.AddFiles([]string{"testfile.txt"}); err != nil {
+		t.Fatalf("failed to stage in submodule: %v", err)
+	}
+	if _, _, err := sub.Commit(CommitOptions{Message: "Change"}); err != nil {
+		t.Fatalf("failed to commit in submodule: %v", err)
+	}
+	if submodules, _ = g.GetSubmodules(); !submodules[0].OutOfDate() || !submodules[0].Status.CommitChanged {
+		t.Errorf("expected an out-of-date submodule, got %+v", submodules[0])
+	}
+	if submodules[0].RecordedSHA != lib.RecordedSHA {
+		t.Errorf("expected the recorded commit %s, got %s", lib.RecordedSHA, submodules[0].RecordedSHA)
+	}
+
+	// Deinitialized submodules are initialized and checked out again by an update.
+	cmd = exec.Command("git", "submodule", "deinit", "--force", "libs/lib")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to deinit submodule: %v: %s", err, output)
+	}
+	if submodules, _ = g.GetSubmodules(); submodules[0].Initialized || submodules[0].CheckedOutSHA != "" {
+		t.Errorf("expected an uninitialized submodule, got %+v", submodules[0])
+	}
+	if _, _, err := g.SyncSubmodules(); err != nil {
+		t.Errorf("SyncSubmodules() failed: %v", err)
+	}
+	if _, _, err := g.InitSubmodules("libs/lib"); err != nil {
+		t.Fatalf("InitSubmodules() failed: %v", err)
+	}
+	allowFile := g.WithEnv("GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=protocol.file.allow", "GIT_CONFIG_VALUE_0=always")
+	if _, _, err := allowFile.UpdateSubmodules(SubmoduleUpdateOptions{Paths: []string{"libs/lib"}}); err != nil {
+		t.Fatalf("UpdateSubmodules() failed: %v", err)
+	}
+	if submodules, _ = g.GetSubmodules(); !submodules[0].Initialized || submodules[0].OutOfDate() {
+		t.Errorf("expected an up-to-date submodule, got %+v", submodules[0])
+	}
+}
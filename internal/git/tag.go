@@ -2,6 +2,7 @@ package git
 
 import (
 	"fmt"
+	"strings"
 )
 
 // TagOptions specifies the options for managing tags.
@@ -9,25 +10,25 @@ type TagOptions struct {
 	Create  bool
 	Delete  bool
 	Name    string
-	Message string
+	Message string // Creates an annotated tag if set, a lightweight tag otherwise.
 	Commit  string
 }
 
 // ManageTag creates, lists, deletes or verifies a tag object signed with GPG.
-func (g *GitCommands) ManageTag(options TagOptions) (string, error) {
+func (g *GitCommands) ManageTag(options TagOptions) (string, string, error) {
 	args := []string{"tag"}
 
 	if options.Delete {
 		if options.Name == "" {
-			return "", fmt.Errorf("tag name is required for deletion")
+			return "", "", fmt.Errorf("tag name is required for deletion")
 		}
 		args = append(args, "-d", options.Name)
 	} else if options.Create {
 		if options.Name == "" {
-			return "", fmt.Errorf("tag name is required for creation")
+			return "", "", fmt.Errorf("tag name is required for creation")
 		}
 		if options.Message != "" {
-			args = append(args, "-m", options.Message)
+			args = append(args, "-a", "-m", options.Message)
 		}
 		args = append(args, options.Name)
 		if options.Commit != "" {
@@ -35,10 +36,47 @@ func (g *GitCommands) ManageTag(options TagOptions) (string, error) {
 		}
 	}
 
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return string(output), cmdStr, fmt.Errorf("git tag command failed: %w", err)
+	}
+
+	return string(output), cmdStr, nil
+}
+
+// Tag represents a git tag.
+type Tag struct {
+	Name      string
+	Commit    string // The abbreviated hash of the tagged commit.
+	Annotated bool
+	Message   string // The subject of the tag message; empty for lightweight tags.
+}
+
+// GetTags lists the tags, the most recently created first.
+func (g *GitCommands) GetTags() ([]*Tag, error) {
+	format := "%(refname:short)%00%(objecttype)%00%(objectname:short)%00%(*objectname:short)%00%(contents:subject)"
+	args := []string{"for-each-ref", "--sort=-creatordate", fmt.Sprintf("--format=%s", format), "refs/tags/"}
+
 	output, _, err := g.executeCommand(args...)
 	if err != nil {
-		return string(output), fmt.Errorf("git tag command failed: %w", err)
+		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
 
-	return string(output), nil
+	var tags []*Tag
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 5 {
+			continue
+		}
+		tag := &Tag{Name: fields[0], Commit: fields[2]}
+		if fields[1] == "tag" {
+			// The object of an annotated tag is the tag itself, which points
+			// to the commit.
+			tag.Annotated = true
+			tag.Commit = fields[3]
+			tag.Message = fields[4]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
 }
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Worktree represents a working tree attached to the repository.
+type Worktree struct {
+	Path           string
+	Head           string // The commit checked out in the worktree.
+	Branch         string // The branch checked out in the worktree, empty if HEAD is detached.
+	Main           bool   // Whether this is the main worktree, which holds the repository.
+	Bare           bool
+	Detached       bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool // Whether the worktree directory is gone, so `git worktree prune` removes it.
+	PrunableReason string
+}
+
+// GetWorktrees lists the main worktree followed by the linked worktrees.
+func (g *GitCommands) GetWorktrees() ([]*Worktree, error) {
+	output, _, err := g.executeCommand("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	return ParseWorktrees(output), nil
+}
+
+// ParseWorktrees parses the output of `git worktree list --porcelain`, which
+// describes each worktree in a block of lines separated by an empty line.
+func ParseWorktrees(output string) []*Worktree {
+	var worktrees []*Worktree
+	var current *Worktree
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			current = &Worktree{Path: value, Main: len(worktrees) == 0}
+			worktrees = append(worktrees, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
+		}
+	}
+	return worktrees
+}
+
+// WorktreeOptions specifies the options for adding a worktree.
+type WorktreeOptions struct {
+	Path      string
+	Branch    string // The branch to check out in the worktree.
+	NewBranch bool   // Whether to create Branch, starting at Base.
+	Base      string // The commit a new branch starts at, HEAD if empty.
+}
+
+// AddWorktree creates a worktree at a path and checks out an existing or a new
+// branch in it.
+func (g *GitCommands) AddWorktree(options WorktreeOptions) (string, string, error) {
+	if options.Path == "" {
+		return "", "", fmt.Errorf("worktree path is required")
+	}
+	if options.Branch == "" {
+		return "", "", fmt.Errorf("branch name is required")
+	}
+
+	args := []string{"worktree", "add"}
+	if options.NewBranch {
+		args = append(args, "-b", options.Branch, options.Path)
+		if options.Base != "" {
+			args = append(args, options.Base)
+		}
+	} else {
+		args = append(args, options.Path, options.Branch)
+	}
+
+	output, cmdStr, err := g.executeCommandWithOptions(execOptions{includeStderr: true}, args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to add worktree %s: %w", options.Path, err)
+	}
+	return output, cmdStr, nil
+}
+
+// RemoveWorktree deletes a linked worktree. Unless force is set, git refuses
+// to remove a worktree with uncommitted changes.
+func (g *GitCommands) RemoveWorktree(path string, force bool) (string, string, error) {
+	if path == "" {
+		return "", "", fmt.Errorf("worktree path is required")
+	}
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, path)
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to remove worktree %s: %w", path, err)
+	}
+	return output, cmdStr, nil
+}
+
+// LockWorktree keeps a linked worktree from being pruned, moved or removed,
+// e.g. while it is on a removable drive.
+func (g *GitCommands) LockWorktree(path, reason string) (string, string, error) {
+	if path == "" {
+		return "", "", fmt.Errorf("worktree path is required")
+	}
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, path)
+
+	output, cmdStr, err := g.executeCommand(args...)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to lock worktree %s: %w", path, err)
+	}
+	return output, cmdStr, nil
+}
+
+// UnlockWorktree allows a locked worktree to be pruned, moved or removed again.
+func (g *GitCommands) UnlockWorktree(path string) (string, string, error) {
+	if path == "" {
+		return "", "", fmt.Errorf("worktree path is required")
+	}
+	output, cmdStr, err := g.executeCommand("worktree", "unlock", path)
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to unlock worktree %s: %w", path, err)
+	}
+	return output, cmdStr, nil
+}
+
+// PruneWorktrees removes the administrative files of worktrees whose
+// directories have been deleted.
+func (g *GitCommands) PruneWorktrees() (string, string, error) {
+	output, cmdStr, err := g.executeCommand("worktree", "prune")
+	if err != nil {
+		return output, cmdStr, fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return output, cmdStr, nil
+}
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleWorktrees = "worktree /repo\n" +
+	"HEAD 1111111111111111111111111111111111111111\n" +
+	"branch refs/heads/main\n" +
+	"\n" +
+	"worktree /repo-hotfix\n" +
+	"HEAD 2222222222222222222222222222222222222222\n" +
+	"branch refs/heads/hotfix\n" +
+	"locked on a usb drive\n" +
+	"\n" +
+	"worktree /repo-review\n" +
+	"HEAD 3333333333333333333333333333333333333333\n" +
+	"detached\n" +
+	"prunable gitdir file points to non-existent location\n"
+
+func TestParseWorktrees(t *testing.T) {
+	worktrees := ParseWorktrees(sampleWorktrees)
+	if len(worktrees) != 3 {
+		t.Fatalf("expected 3 worktrees, got %d: %+v", len(worktrees), worktrees)
+	}
+
+	main, hotfix, review := worktrees[0], worktrees[1], worktrees[2]
+	if !main.Main || main.Path != "/repo" || main.Branch != "main" || main.Locked {
+		t.Errorf("unexpected main worktree: %+v", main)
+	}
+	if hotfix.Main || hotfix.Branch != "hotfix" || !hotfix.Locked || hotfix.LockReason != "on a usb drive" {
+		t.Errorf("unexpected locked worktree: %+v", hotfix)
+	}
+	if !review.Detached || review.Branch != "" || !review.Prunable || review.Head != "3333333333333333333333333333333333333333" {
+		t.Errorf("unexpected prunable worktree: %+v", review)
+	}
+}
+
+func TestGitCommands_Worktrees(t *testing.T) {
+	repoPath, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	g := NewGitCommands()
+	if _, _, err := g.AddWorktree(WorktreeOptions{Path: "../wt"}); err == nil {
+		t.Error("AddWorktree() without a branch should fail")
+	}
+
+	parent := t.TempDir()
+	newPath, existingPath := filepath.Join(parent, "new"), filepath.Join(parent, "existing")
+	if _, _, err := g.AddWorktree(WorktreeOptions{Path: newPath, Branch: "feature", NewBranch: true}); err != nil {
+		t.Fatalf("AddWorktree() with a new branch failed: %v", err)
+	}
+	if _, _, err := g.ManageBranch(BranchOptions{Create: true, Name: "review"}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	if _, _, err := g.AddWorktree(WorktreeOptions{Path: existingPath, Branch: "review"}); err != nil {
+		t.Fatalf("AddWorktree() with an existing branch failed: %v", err)
+	}
+
+	worktrees, err := g.GetWorktrees()
+	if err != nil {
+		t.Fatalf("GetWorktrees() failed: %v", err)
+	}
+	// Linked worktrees are listed by path.
+	if len(worktrees) != 3 || !worktrees[0].Main || worktrees[1].Branch != "review" || worktrees[2].Branch != "feature" {
+		t.Fatalf("unexpected worktrees: %+v, %+v, %+v", worktrees[0], worktrees[1], worktrees[2])
+	}
+	if root, _ := filepath.EvalSymlinks(repoPath); worktrees[0].Path != root {
+		t.Errorf("expected the main worktree at %s, got %s", root, worktrees[0].Path)
+	}
+
+	// Branches checked out in another worktree are marked.
+	branches, err := g.GetBranches()
+	if err != nil {
+		t.Fatalf("GetBranches() failed: %v", err)
+	}
+	for _, b := range branches {
+		if b.Name == "feature" && b.Worktree != worktrees[2].Path {
+			t.Errorf("expected feature to be in worktree %s, got %q", worktrees[2].Path, b.Worktree)
+		}
+		if b.IsCurrent && b.Worktree != "" {
+			t.Errorf("expected no worktree for the current branch, got %q", b.Worktree)
+		}
+	}
+
+	if _, _, err := g.LockWorktree(newPath, "in use"); err != nil {
+		t.Fatalf("LockWorktree() failed: %v", err)
+	}
+	if worktrees, _ = g.GetWorktrees(); !worktrees[2].Locked || worktrees[2].LockReason != "in use" {
+		t.Errorf("expected a locked worktree, got %+v", worktrees[2])
+	}
+	if _, _, err := g.RemoveWorktree(newPath, false); err == nil {
+		t.Error("RemoveWorktree() of a locked worktree should fail")
+	}
+	if _, _, err := g.UnlockWorktree(newPath); err != nil {
+		t.Fatalf("UnlockWorktree() failed: %v", err)
+	}
+	if _, _, err := g.RemoveWorktree(newPath, false); err != nil {
+		t.Fatalf("RemoveWorktree() failed: %v", err)
+	}
+
+	// A worktree whose directory is gone is pruned.
+	if err := os.RemoveAll(existingPath); err != nil {
+		t.Fatalf("failed to delete worktree directory: %v", err)
+	}
+	if worktrees, _ = g.GetWorktrees(); len(worktrees) != 2 || !worktrees[1].Prunable {
+		t.Errorf("expected a prunable worktree, got %+v", worktrees)
+	}
+	if _, _, err := g.PruneWorktrees(); err != nil {
+		t.Fatalf("PruneWorktrees() failed: %v", err)
+	}
+	if worktrees, _ = g.GetWorktrees(); len(worktrees) != 1 {
+		t.Errorf("expected only the main worktree, got %+v", worktrees)
+	}
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// bisectRunMsg is sent when a command to run on the remaining bisect commits
+// has been entered.
+type bisectRunMsg struct {
+	command string
+}
+
+// isBisecting reports whether a bisect is in progress.
+func (m Model) isBisecting() bool {
+	return m.repoState.Operation == git.OperationBisect
+}
+
+// bisectMark gives a verdict on the selected commit. Marking a commit as bad
+// starts a new bisect if none is in progress.
+func (m *Model) bisectMark(term git.BisectTerm, sha string) tea.Cmd {
+	if !m.isBisecting() && term != git.BisectBad {
+		return func() tea.Msg {
+			return errMsg{fmt.Errorf("mark a bad commit to start a bisect first")}
+		}
+	}
+	bisecting := m.isBisecting()
+	return func() tea.Msg {
+		var cmdStr string
+		var err error
+		if bisecting {
+			_, cmdStr, err = m.git.BisectMark(term, sha)
+		} else {
+			_, cmdStr, err = m.git.BisectStart(sha)
+		}
+		if err != nil {
+			return errMsg{err}
+		}
+		return commandExecutedMsg{cmdStr}
+	}
+}
+
+// promptBisectRun asks for a command to mark the remaining commits automatically.
+func (m *Model) promptBisectRun() tea.Cmd {
+	if m.repoState.Bisect == nil || !m.repoState.Bisect.HasRange() {
+		return func() tea.Msg {
+			return errMsg{fmt.Errorf("mark a good and a bad commit before running a bisect command")}
+		}
+	}
+	m.mode = modeInput
+	m.promptTitle = "Command to test each commit (exit 0 = good, 125 = skip)"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	m.inputCallback = func(command string) tea.Cmd {
+		if strings.TrimSpace(command) == "" {
+			return nil
+		}
+		return func() tea.Msg { return bisectRunMsg{command: command} }
+	}
+	return nil
+}
+
+// handleBisectRunMsg runs the bisect command and streams its output into the Main panel.
+func (m Model) handleBisectRunMsg(msg bisectRunMsg) (Model, tea.Cmd) {
+	gc := m.git
+	cmd := m.startOutputView("Bisect run: "+msg.command, func(w io.Writer) (string, error) {
+		_, cmdStr, err := gc.BisectRun(msg.command, w)
+		return cmdStr, err
+	})
+	return m, cmd
+}
+
+// bisectBanner describes the progress of a bisect for the Status panel.
+func bisectBanner(state *git.RepoState) string {
+	bisect := state.Bisect
+	switch {
+	case bisect == nil:
+		return "BISECTING"
+	case bisect.FirstBad != "":
+		return fmt.Sprintf("BISECT DONE: first bad commit is %s", shortSHA(bisect.FirstBad))
+	case !bisect.HasRange():
+		return "BISECTING: mark a good commit"
+	}
+	return fmt.Sprintf("BISECTING: %d revisions left (roughly %d steps)", bisect.Remaining, bisect.Steps)
+}
+
+// bisectLabel returns a styled label for a commit that has been marked during
+// the bisect in progress, or an empty string.
+func (m Model) bisectLabel(sha string) string {
+	bisect := m.repoState.Bisect
+	if bisect == nil || sha == "" {
+		return ""
+	}
+	matches := func(full string) bool {
+		return full != "" && strings.HasPrefix(full, sha)
+	}
+	matchesAny := func(commits []string) bool {
+		for _, commit := range commits {
+			if matches(commit) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case matches(bisect.FirstBad):
+		return m.theme.GitConflicted.Render("[first bad]")
+	case matches(bisect.Bad):
+		return m.theme.DiffRemoved.Render("[bad]")
+	case matchesAny(bisect.Good):
+		return m.theme.DiffAdded.Render("[good]")
+	case matchesAny(bisect.Skipped):
+		return m.theme.GitUntracked.Render("[skip]")
+	case matches(bisect.Current):
+		return m.theme.DiffHunk.Render("[current]")
+	}
+	return ""
+}
+
+// shortSHA abbreviates a full commit hash for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
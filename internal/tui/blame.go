@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// blameAuthorWidth is the width of the author column of the blame view.
+const blameAuthorWidth = 12
+
+// blameState holds the state of the blame view in the Main panel.
+type blameState struct {
+	path     string
+	revision string // The blamed revision, empty for the work tree.
+	lines    []git.BlameLine
+	line     int // Index of the selected line.
+}
+
+// blameLoadedMsg is sent when the blame of a file has been loaded.
+type blameLoadedMsg struct {
+	path     string
+	revision string
+	lines    []git.BlameLine
+	err      error
+}
+
+// enterBlameView switches the Main panel to the blame view of the given file
+// and moves focus to it.
+func (m *Model) enterBlameView(path string) tea.Cmd {
+	m.blame = blameState{path: path}
+	m.mainView = mainViewBlame
+	m.focusedPanel = MainPanel
+	return m.loadBlame()
+}
+
+// loadBlame returns a command that blames the file of the blame view.
+func (m *Model) loadBlame() tea.Cmd {
+	path, revision := m.blame.path, m.blame.revision
+	return func() tea.Msg {
+		lines, err := m.git.BlameFile(path, revision)
+		return blameLoadedMsg{path: path, revision: revision, lines: lines, err: err}
+	}
+}
+
+// handleBlameLoadedMsg stores a freshly loaded blame and renders it.
+func (m Model) handleBlameLoadedMsg(msg blameLoadedMsg) (Model, tea.Cmd) {
+	if m.mainView != mainViewBlame || msg.path != m.blame.path || msg.revision != m.blame.revision {
+		return m, nil // Stale message.
+	}
+	if msg.err != nil {
+		cmd := m.exitMainView()
+		return m, tea.Batch(cmd, func() tea.Msg { return errMsg{msg.err} })
+	}
+	m.blame.lines = msg.lines
+	if m.blame.line >= len(msg.lines) {
+		m.blame.line = max(len(msg.lines)-1, 0)
+	}
+	m.renderBlameView()
+	return m, nil
+}
+
+// selectedBlameLine returns the line under the cursor of the blame view, or
+// nil if there is none.
+func (m Model) selectedBlameLine() *git.BlameLine {
+	if m.blame.line < 0 || m.blame.line >= len(m.blame.lines) {
+		return nil
+	}
+	return &m.blame.lines[m.blame.line]
+}
+
+// handleBlameKeys handles keybindings while the blame view is shown.
+func (m *Model) handleBlameKeys(msg tea.KeyMsg) tea.Cmd {
+	line := m.selectedBlameLine()
+	if line == nil {
+		return nil
+	}
+
+	switch {
+	case Matches(msg, m.keymap["up"]):
+		if m.blame.line > 0 {
+			m.blame.line--
+			m.renderBlameView()
+		}
+
+	case Matches(msg, m.keymap["down"]):
+		if m.blame.line < len(m.blame.lines)-1 {
+			m.blame.line++
+			m.renderBlameView()
+		}
+
+	case Matches(msg, m.keymap["goto_commit"]):
+		if line.Commit.IsUncommitted() {
+			return func() tea.Msg { return errMsg{fmt.Errorf("line %d is not committed yet", line.Line)} }
+		}
+		return m.jumpToCommit(line.Commit.SHA)
+
+	case Matches(msg, m.keymap["blame_parent"]):
+		commit := line.Commit
+		if commit.IsUncommitted() {
+			return func() tea.Msg { return errMsg{fmt.Errorf("line %d is not committed yet", line.Line)} }
+		}
+		if commit.Previous == "" {
+			return func() tea.Msg {
+				return errMsg{fmt.Errorf("%s added %s, it has no earlier revision", shortSHA(commit.SHA), commit.Path)}
+			}
+		}
+		// Keep the cursor near the same place, which is the best guess for
+		// where the line was before the commit.
+		m.blame.path = commit.PreviousPath
+		m.blame.revision = commit.Previous
+		m.blame.lines = nil
+		m.renderBlameView()
+		return m.loadBlame()
+	}
+	return nil
+}
+
+// jumpToCommit leaves the blame view and selects the given commit in the
+// Commits panel. If the commit is older than the loaded ones, the commit log
+// is loaded page by page until it is listed.
+func (m *Model) jumpToCommit(sha string) tea.Cmd {
+	cursor := -1
+	if m.panels[CommitsPanel].tab == defaultTab {
+		for i, line := range m.panels[CommitsPanel].lines {
+			if short := commitLineSHA(line); short != "" && strings.HasPrefix(sha, short) {
+				cursor = i
+				break
+			}
+		}
+	}
+	if cursor < 0 && m.panels[CommitsPanel].tab == defaultTab && m.fileHistory.path == "" && m.commitLog.more {
+		m.commitLog.jumpTo = sha
+		if m.commitLog.loading {
+			return nil // The page being loaded resumes the jump.
+		}
+		return m.loadCommitPage()
+	}
+	if cursor < 0 {
+		return func() tea.Msg {
+			return errMsg{fmt.Errorf("commit %s is not listed in the Commits panel", shortSHA(sha))}
+		}
+	}
+
+	m.mainView = mainViewDiff
+	m.blame = blameState{}
+	m.focusedPanel = CommitsPanel
+	m.activeSourcePanel = CommitsPanel
+	*m = m.recalculateLayout()
+	p := &m.panels[CommitsPanel]
+	p.cursor = cursor
+	if cursor < p.viewport.YOffset || cursor >= p.viewport.YOffset+p.viewport.Height {
+		p.viewport.SetYOffset(cursor)
+	}
+	m.panels[MainPanel].viewport.GotoTop()
+	return m.updateMainPanel()
+}
+
+// renderBlameView renders the blamed lines into the Main panel, keeping the
+// selected line in view.
+func (m *Model) renderBlameView() {
+	var builder strings.Builder
+	for i, line := range m.blame.lines {
+		commit := line.Commit
+		sha, author, date := shortSHA(commit.SHA), commit.Author, commit.Time.Format("2006-01-02")
+		if commit.IsUncommitted() {
+			sha, author, date = "", "Not Committed", strings.Repeat(" ", 10)
+		}
+		if runes := []rune(author); len(runes) > blameAuthorWidth {
+			author = string(runes[:blameAuthorWidth-1]) + "…"
+		}
+		info := fmt.Sprintf("%-7s %-*s %s %4d ", sha, blameAuthorWidth, author, date, line.Line)
+
+		gutter := " "
+		if i == m.blame.line {
+			gutter = m.theme.ActiveBorder.Style.Render(hunkSelectedGutter)
+			info = m.theme.SelectedLine.Render(info)
+		} else {
+			info = m.theme.CommitSHA.Render(info)
+		}
+		builder.WriteString(gutter + info + line.Content + "\n")
+	}
+
+	content := strings.TrimRight(builder.String(), "\n")
+	if len(m.blame.lines) == 0 {
+		content = initialContentLoading
+	}
+	vp := &m.panels[MainPanel].viewport
+	m.panels[MainPanel].content = content
+	vp.SetContent(content)
+	if m.blame.line < vp.YOffset || m.blame.line >= vp.YOffset+vp.Height {
+		vp.SetYOffset(m.blame.line - vp.Height/3)
+	}
+}
+
+// blameTitle returns the Main panel title while the blame view is shown.
+func (m Model) blameTitle() string {
+	title := fmt.Sprintf("Blame: %s", m.blame.path)
+	if m.blame.revision != "" {
+		title += " @ " + shortSHA(m.blame.revision)
+	}
+	line := m.selectedBlameLine()
+	if line == nil {
+		return title
+	}
+	return fmt.Sprintf("%s (line %d/%d) %s", title, m.blame.line+1, len(m.blame.lines), line.Commit.Summary)
+}
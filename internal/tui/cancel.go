@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// Default timeouts of the git commands, used unless the config sets them.
+const (
+	// Local commands may run hooks or rewrite the index, which a timeout
+	// would interrupt halfway; they can be cancelled instead.
+	defaultCommandTimeout     = 0
+	defaultLongRunningTimeout = 0 // Remote commands may take long; they can be cancelled instead.
+)
+
+// timeoutsConfig is the [timeouts] section of config.toml. The timeouts are
+// durations such as "30s" or "2m"; "0" disables the timeout.
+type timeoutsConfig struct {
+	Command     string `toml:"command"`
+	LongRunning string `toml:"long_running"`
+}
+
+// gitTimeouts returns the configured timeouts, falling back to the defaults
+// for any that are unset or invalid.
+func (c timeoutsConfig) gitTimeouts() git.Timeouts {
+	return git.Timeouts{
+		Command:     parseTimeout(c.Command, defaultCommandTimeout),
+		LongRunning: parseTimeout(c.LongRunning, defaultLongRunningTimeout),
+	}
+}
+
+func parseTimeout(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return fallback
+	}
+	return timeout
+}
+
+// isCancelled reports whether err is from a git command that was cancelled,
+// whose result is of no interest anymore.
+func isCancelled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// bindContext binds the git commands to a new context, which cancelOperations
+// cancels.
+func (m *Model) bindContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.git = m.git.WithContext(ctx)
+	m.cancel = cancel
+}
+
+// cancelOperations kills every running git command, e.g. one waiting for a
+// credential prompt or a slow hook, and refreshes the panels.
+func (m *Model) cancelOperations() tea.Cmd {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.bindContext()
+	return tea.Batch(
+		m.fetchPanelContent(StatusPanel),
+		m.fetchPanelContent(FilesPanel),
+		m.fetchPanelContent(BranchesPanel),
+		m.fetchPanelContent(CommitsPanel),
+		m.fetchPanelContent(StashPanel),
+		m.updateMainPanel(),
+	)
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleCopiedCommit marks the given commit for cherry-picking, or unmarks it
+// if it is already marked.
+func (m *Model) toggleCopiedCommit(sha string) {
+	for i, copied := range m.copiedCommits {
+		if copied == sha {
+			m.copiedCommits = append(m.copiedCommits[:i], m.copiedCommits[i+1:]...)
+			return
+		}
+	}
+	m.copiedCommits = append(m.copiedCommits, sha)
+	m.sortCopiedCommits()
+}
+
+// sortCopiedCommits orders the marked commits the way they must be applied:
+// commits marked on other branches first, then the commits of the current log
+// from oldest to newest.
+func (m *Model) sortCopiedCommits() {
+	position := make(map[string]int)
+	for i, line := range m.panels[CommitsPanel].lines {
+		if sha := commitLineSHA(line); sha != "" {
+			position[sha] = i
+		}
+	}
+	rank := func(sha string) int {
+		if i, ok := position[sha]; ok {
+			return -i // The log lists the newest commit first.
+		}
+		return -len(m.panels[CommitsPanel].lines) - 1
+	}
+	sort.SliceStable(m.copiedCommits, func(i, j int) bool {
+		return rank(m.copiedCommits[i]) < rank(m.copiedCommits[j])
+	})
+}
+
+// isCommitCopied reports whether a commit is marked for cherry-picking.
+func (m Model) isCommitCopied(sha string) bool {
+	for _, copied := range m.copiedCommits {
+		if copied == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// pasteCopiedCommits asks for confirmation and cherry-picks the marked commits
+// onto HEAD.
+func (m *Model) pasteCopiedCommits() tea.Cmd {
+	if len(m.copiedCommits) == 0 {
+		return nil
+	}
+	commits := append([]string{}, m.copiedCommits...)
+	m.mode = modeConfirm
+	m.confirmMessage = fmt.Sprintf("Cherry-pick %d commit(s) onto %s?", len(commits), m.repoState.Branch)
+	m.confirmCallback = func(confirmed bool) tea.Cmd {
+		m.mode = modeNormal
+		if !confirmed {
+			return nil
+		}
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.CherryPick(commits)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return nil
+}
+
+// commitLineSHA returns the commit of a line in the Commits panel, or an empty
+// string for lines that only contain graph edges.
+func commitLineSHA(line string) string {
+	parts := strings.Split(line, "\t")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// decorateCommitLine prefixes the subject of a line in the Commits panel with
+// markers for commits copied for cherry-picking or marked during a bisect.
+func (m Model) decorateCommitLine(line string) string {
+	sha := commitLineSHA(line)
+	if sha == "" {
+		return line
+	}
+	var markers []string
+	if m.isCommitCopied(sha) {
+		markers = append(markers, copiedCommitMarker)
+	}
+	if label := m.bisectLabel(sha); label != "" {
+		markers = append(markers, label)
+	}
+	if len(markers) == 0 {
+		return line
+	}
+	parts := strings.SplitN(line, "\t", 4)
+	parts[len(parts)-1] = strings.Join(markers, " ") + " " + parts[len(parts)-1]
+	return strings.Join(parts, "\t")
+}
@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// commitLogState holds the paging state of the commit log in the Commits panel.
+type commitLogState struct {
+	limit   int             // The number of commits a refresh loads.
+	more    bool            // Whether the log may have more commits than loaded.
+	loading bool            // Whether the next page is being loaded.
+	logs    []git.CommitLog // The commit on each line of the commit log.
+	graph   []graphRow      // The graph on each line of the commit log.
+	layout  graphLayout     // The graph below the loaded commits, which the next page continues.
+	jumpTo  string          // A commit to select from the blame view once its page has been loaded.
+}
+
+// commitLogUpdatedMsg is sent when the commit log, or the next page of it, has
+// been fetched.
+type commitLogUpdatedMsg struct {
+	logs  []git.CommitLog
+	skip  int // The number of commits before the page, 0 if the log was refreshed.
+	limit int
+}
+
+// handleCommitLogUpdatedMsg renders the commit log into the Commits panel,
+// keeping the cursor on the selected commit, or appends the next page to it.
+func (m Model) handleCommitLogUpdatedMsg(msg commitLogUpdatedMsg) (Model, tea.Cmd) {
+	if m.rebaseTodo.active || m.panels[CommitsPanel].tab != defaultTab || m.fileHistory.path != "" {
+		return m, nil // Stale message.
+	}
+	if msg.skip > 0 {
+		return m.appendCommitLogPage(msg)
+	}
+	if msg.limit < m.commitLog.limit {
+		// The refresh was fetched before the next page was loaded, so it is
+		// fetched again with the page.
+		return m, m.fetchPanelContent(CommitsPanel)
+	}
+
+	p := &m.panels[CommitsPanel]
+	var selected string
+	if p.cursor < len(p.lines) {
+		selected = commitLineSHA(p.lines[p.cursor])
+	}
+
+	m.commitLog.layout = graphLayout{}
+	graph := m.commitLog.layout.add(msg.logs)
+	lines := commitLogLines(msg.logs, graph)
+	m.commitLog.logs = msg.logs
+	m.commitLog.graph = graph
+	m.commitLog.more = msg.limit > 0 && len(msg.logs) >= msg.limit
+	m.commitLog.loading = false
+
+	cursor := min(p.cursor, max(len(lines)-1, 0))
+	if selected != "" {
+		for i, line := range lines {
+			if commitLineSHA(line) == selected {
+				cursor = i
+				break
+			}
+		}
+	}
+	// Scroll by as much as the selected commit moved, so it stays in place.
+	offset := max(p.viewport.YOffset+cursor-p.cursor, 0)
+
+	content := strings.Join(lines, "\n")
+	p.lines = lines
+	p.content = content
+	p.viewport.SetContent(content)
+	p.cursor = cursor
+	p.viewport.SetYOffset(offset)
+	if jump := m.resumeJumpToCommit(); jump != nil {
+		return m, jump
+	}
+	return m, m.updateMainPanel()
+}
+
+// appendCommitLogPage appends the next page of the commit log to the Commits
+// panel, continuing the graph where the loaded commits left it.
+func (m Model) appendCommitLogPage(msg commitLogUpdatedMsg) (Model, tea.Cmd) {
+	if msg.skip != len(m.commitLog.logs) {
+		return m, nil // The log has been refreshed since the page was requested.
+	}
+
+	graph := m.commitLog.layout.add(msg.logs)
+	m.commitLog.logs = append(m.commitLog.logs[:msg.skip:msg.skip], msg.logs...)
+	m.commitLog.graph = append(m.commitLog.graph[:msg.skip:msg.skip], graph...)
+	m.commitLog.limit = msg.skip + msg.limit
+	m.commitLog.more = len(msg.logs) >= msg.limit
+	m.commitLog.loading = false
+
+	p := &m.panels[CommitsPanel]
+	p.lines = append(p.lines[:msg.skip:msg.skip], commitLogLines(msg.logs, graph)...)
+	p.content = strings.Join(p.lines, "\n")
+	offset := p.viewport.YOffset
+	p.viewport.SetContent(p.content)
+	p.viewport.SetYOffset(offset)
+	return m, m.resumeJumpToCommit()
+}
+
+// resumeJumpToCommit continues a jump from the blame view to a commit that was
+// not loaded yet, unless the blame view has been left since.
+func (m *Model) resumeJumpToCommit() tea.Cmd {
+	sha := m.commitLog.jumpTo
+	m.commitLog.jumpTo = ""
+	if sha == "" || m.mainView != mainViewBlame {
+		return nil
+	}
+	return m.jumpToCommit(sha)
+}
+
+// commitLogLines returns the lines of the Commits panel for commits and their
+// graph rows.
+func commitLogLines(logs []git.CommitLog, graph []graphRow) []string {
+	lines := make([]string, len(logs))
+	for i, log := range logs {
+		lines[i] = fmt.Sprintf("%s\t%s\t%s\t%s", graph[i], log.SHA, log.AuthorInitials, log.Subject)
+	}
+	return lines
+}
+
+// loadMoreCommits returns a command that loads the next page of the commit log
+// once the cursor nears the end of the loaded commits.
+func (m *Model) loadMoreCommits() tea.Cmd {
+	p := m.panels[CommitsPanel]
+	if !m.commitLog.more || m.commitLog.loading || p.tab != defaultTab || m.fileHistory.path != "" {
+		return nil
+	}
+	if p.cursor < len(p.lines)-commitLoadMargin {
+		return nil
+	}
+	return m.loadCommitPage()
+}
+
+// loadCommitPage returns a command that loads the next page of the commit log.
+func (m *Model) loadCommitPage() tea.Cmd {
+	m.commitLog.loading = true
+	skip := len(m.commitLog.logs)
+	return func() tea.Msg {
+		logs, err := m.git.GetCommitLogsGraph(skip, commitPageSize)
+		if err != nil {
+			if isCancelled(err) {
+				return nil
+			}
+			return errMsg{err}
+		}
+		return commitLogUpdatedMsg{logs: logs, skip: skip, limit: commitPageSize}
+	}
+}
+
+// commitLogLine returns the commit and the graph on a line of the Commits
+// panel, or nil if the panel does not show the commit log.
+func (m Model) commitLogLine(line int) (*git.CommitLog, graphRow) {
+	if m.rebaseTodo.active || m.panels[CommitsPanel].tab != defaultTab || m.fileHistory.path != "" {
+		return nil, nil
+	}
+	if line < 0 || line >= len(m.commitLog.logs) || line >= len(m.commitLog.graph) {
+		return nil, nil
+	}
+	return &m.commitLog.logs[line], m.commitLog.graph[line]
+}
+
+// renderCommitLogLine renders a line of the commit log on top of base, e.g.
+// the style of the selected line: the graph, the commit, its date and author,
+// the refs pointing at it and its subject. The date is only shown, and the
+// author is only named, if the panel is wide enough.
+func (m Model) renderCommitLogLine(log *git.CommitLog, graph graphRow, subject string, width int, base lipgloss.Style) string {
+	on := func(style lipgloss.Style) lipgloss.Style { return style.Inherit(base) }
+
+	columns := []string{graph.render(m.theme, base), on(m.theme.CommitSHA).Render(log.SHA)}
+	if width >= commitDateMinWidth && !log.AuthorDate.IsZero() {
+		age := formatAge(time.Since(log.AuthorDate))
+		columns = append(columns, on(m.theme.BranchDate).Render(fmt.Sprintf("%3s", age)))
+	}
+	author := log.AuthorInitials
+	if width >= commitAuthorMinWidth {
+		author = log.AuthorName
+		if runes := []rune(author); len(runes) > commitAuthorWidth {
+			author = string(runes[:commitAuthorWidth-1]) + "…"
+		}
+		author = fmt.Sprintf("%-*s", commitAuthorWidth, author)
+	}
+	authorStyle := m.theme.CommitAuthor
+	if len(log.Parents) > 1 {
+		authorStyle = m.theme.CommitMerge
+	}
+	columns = append(columns, on(authorStyle).Render(author))
+	if labels := m.renderRefLabels(log.Refs, base); labels != "" {
+		columns = append(columns, labels)
+	}
+	columns = append(columns, on(lipgloss.NewStyle()).Render(subject))
+	return strings.Join(columns, on(lipgloss.NewStyle()).Render(" "))
+}
+
+// renderRefLabels renders the refs pointing at a commit the way git log
+// decorates it, e.g. "(HEAD → main, origin/main, tag: v1.0)".
+func (m Model) renderRefLabels(refs []git.Ref, base lipgloss.Style) string {
+	if len(refs) == 0 {
+		return ""
+	}
+	on := func(style lipgloss.Style) lipgloss.Style { return style.Inherit(base) }
+	plain := on(lipgloss.NewStyle())
+
+	labels := make([]string, len(refs))
+	for i, ref := range refs {
+		switch ref.Kind {
+		case git.RefBranch:
+			labels[i] = on(m.theme.CommitBranch).Render(ref.Name)
+			if ref.Head {
+				labels[i] = on(m.theme.CommitRefHead).Render("HEAD →") + plain.Render(" ") + labels[i]
+			}
+		case git.RefRemoteBranch:
+			labels[i] = on(m.theme.CommitRemote).Render(ref.Name)
+		case git.RefTag:
+			labels[i] = on(m.theme.CommitTag).Render("tag: " + ref.Name)
+		case git.RefHead:
+			labels[i] = on(m.theme.CommitRefHead).Render(ref.Name)
+		default:
+			labels[i] = plain.Render(ref.Name)
+		}
+	}
+	return plain.Render("(") + strings.Join(labels, plain.Render(", ")) + plain.Render(")")
+}
+
+// formatAge returns how long ago something happened in the short form of the
+// Branches panel, e.g. "5m", "3d" or "2y".
+func formatAge(d time.Duration) string {
+	const day = 24 * time.Hour
+	d = max(d, 0)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 7*day:
+		return fmt.Sprintf("%dd", d/day)
+	case d < 30*day:
+		return fmt.Sprintf("%dw", d/(7*day))
+	case d < 365*day:
+		return fmt.Sprintf("%dM", d/(30*day))
+	}
+	return fmt.Sprintf("%dy", d/(365*day))
+}
@@ -11,15 +11,19 @@ import (
 var (
 	ConfigDirName       = ".config/gitx"
 	ConfigFileName      = "config.toml"
+	RecentReposFileName = "recent_repos"
 	ConfigDirPath       string
 	ConfigFilePath      string
 	ConfigThemesDirPath string
+	RecentReposFilePath string
 )
 
 // config.toml
 type appConfig struct {
 	Theme       string            `toml:"theme"`
 	Keybindings map[string]string `toml:"keybindings"`
+	Timeouts    timeoutsConfig    `toml:"timeouts"`
+	Workspace   workspaceConfig   `toml:"workspace"`
 }
 
 func load_config() (*appConfig, error) {
@@ -42,6 +46,7 @@ func initializeConfig() error {
 	ConfigDirPath = filepath.Join(homeDir, ConfigDirName)
 	ConfigFilePath = filepath.Join(ConfigDirPath, ConfigFileName)
 	ConfigThemesDirPath = filepath.Join(ConfigDirPath, "themes")
+	RecentReposFilePath = filepath.Join(ConfigDirPath, RecentReposFileName)
 
 	err = os.MkdirAll(ConfigDirPath, 0755)
 	if err != nil {
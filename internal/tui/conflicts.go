@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// conflictState holds the state of the conflict resolution view in the Main panel.
+type conflictState struct {
+	path  string
+	file  *git.ConflictedFile
+	block int // Index of the selected conflict block.
+}
+
+// conflictFileMsg is sent when the conflicted file for the conflict view has been loaded.
+type conflictFileMsg struct {
+	path string
+	file *git.ConflictedFile
+	err  error
+}
+
+// conflictResolvedMsg is sent when a conflicted file has been resolved as a whole.
+type conflictResolvedMsg struct {
+	cmdStr string
+}
+
+// enterConflictView switches the Main panel to the conflict resolution view for
+// the given file and moves focus to it.
+func (m *Model) enterConflictView(file git.FileStatus) tea.Cmd {
+	m.conflicts = conflictState{path: file.Path}
+	m.mainView = mainViewConflicts
+	m.focusedPanel = MainPanel
+	path := file.Path
+	return func() tea.Msg {
+		conflicted, err := m.git.GetConflictedFile(path)
+		return conflictFileMsg{path: path, file: conflicted, err: err}
+	}
+}
+
+// handleConflictFileMsg stores a freshly loaded conflicted file and renders it.
+func (m Model) handleConflictFileMsg(msg conflictFileMsg) (Model, tea.Cmd) {
+	if m.mainView != mainViewConflicts || msg.path != m.conflicts.path {
+		return m, nil // Stale message.
+	}
+	if msg.err != nil {
+		cmd := m.exitMainView()
+		return m, tea.Batch(cmd, func() tea.Msg { return errMsg{msg.err} })
+	}
+	m.conflicts.file = msg.file
+	m.conflicts.block = 0
+	m.renderConflictView()
+	return m, nil
+}
+
+// handleConflictKeys handles keybindings while the conflict view is shown.
+func (m *Model) handleConflictKeys(msg tea.KeyMsg) tea.Cmd {
+	file := m.conflicts.file
+	if file == nil {
+		return nil
+	}
+	blocks := file.Blocks()
+
+	switch {
+	case Matches(msg, m.keymap["up"]):
+		if m.conflicts.block > 0 {
+			m.conflicts.block--
+			m.renderConflictView()
+		}
+
+	case Matches(msg, m.keymap["down"]):
+		if m.conflicts.block < len(blocks)-1 {
+			m.conflicts.block++
+			m.renderConflictView()
+		}
+
+	case Matches(msg, m.keymap["resolve_ours"]):
+		m.toggleResolution(blocks, git.ResolutionOurs)
+
+	case Matches(msg, m.keymap["resolve_theirs"]):
+		m.toggleResolution(blocks, git.ResolutionTheirs)
+
+	case Matches(msg, m.keymap["resolve_both"]):
+		m.toggleResolution(blocks, git.ResolutionBoth)
+
+	case Matches(msg, m.keymap["write_resolution"]):
+		if !file.IsResolved() {
+			return func() tea.Msg {
+				return errMsg{fmt.Errorf("%s still has unresolved conflicts", file.Path)}
+			}
+		}
+		write := func() tea.Msg {
+			_, cmdStr, err := m.git.ResolveConflictedFile(file)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+		return tea.Batch(write, m.exitMainView())
+
+	case Matches(msg, m.keymap["checkout_ours"]):
+		return m.confirmCheckoutConflictSide(file.Path, git.SideOurs)
+
+	case Matches(msg, m.keymap["checkout_theirs"]):
+		return m.confirmCheckoutConflictSide(file.Path, git.SideTheirs)
+	}
+	return nil
+}
+
+// toggleResolution sets the resolution of the selected block, or clears it if
+// it is already set.
+func (m *Model) toggleResolution(blocks []*git.ConflictBlock, resolution git.ConflictResolution) {
+	if m.conflicts.block >= len(blocks) {
+		return
+	}
+	block := blocks[m.conflicts.block]
+	if block.Resolution == resolution {
+		block.Resolution = git.ResolutionNone
+	} else {
+		block.Resolution = resolution
+	}
+	m.renderConflictView()
+}
+
+// confirmCheckoutConflictSide asks for confirmation before resolving a whole
+// file with one side, which discards the changes of the other side.
+func (m *Model) confirmCheckoutConflictSide(path string, side git.ConflictSide) tea.Cmd {
+	m.mode = modeConfirm
+	m.confirmMessage = fmt.Sprintf("Resolve %s using %s version of the whole file?", path, side)
+	m.confirmCallback = func(confirmed bool) tea.Cmd {
+		m.mode = modeNormal
+		if !confirmed {
+			return nil
+		}
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.CheckoutConflictSide(path, side)
+			if err != nil {
+				return errMsg{err}
+			}
+			return conflictResolvedMsg{cmdStr: cmdStr}
+		}
+	}
+	return nil
+}
+
+// handleConflictResolvedMsg leaves the conflict view, if shown, and logs the command.
+func (m Model) handleConflictResolvedMsg(msg conflictResolvedMsg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.mainView == mainViewConflicts {
+		cmd = m.exitMainView()
+	}
+	return m, tea.Batch(cmd, func() tea.Msg { return commandExecutedMsg{msg.cmdStr} })
+}
+
+// renderConflictView renders the conflicted file into the Main panel, marking
+// the selected block and scrolling it into view.
+func (m *Model) renderConflictView() {
+	var builder strings.Builder
+	lineCount := 0
+	selectedOffset := 0
+	blockIndex := 0
+
+	writeLine := func(gutter, line string) {
+		builder.WriteString(gutter + line + "\n")
+		lineCount++
+	}
+
+	for _, chunk := range m.conflicts.file.Chunks {
+		block := chunk.Conflict
+		if block == nil {
+			for _, line := range chunk.Lines {
+				writeLine(" ", line)
+			}
+			continue
+		}
+
+		gutter := " "
+		if blockIndex == m.conflicts.block {
+			gutter = m.theme.ActiveBorder.Style.Render(hunkSelectedGutter)
+			selectedOffset = lineCount - m.panels[MainPanel].viewport.Height/3
+		}
+		blockIndex++
+
+		oursStyle, theirsStyle := m.theme.GitStaged, m.theme.CommitMerge
+		switch block.Resolution {
+		case git.ResolutionOurs:
+			oursStyle, theirsStyle = m.theme.DiffAdded, m.theme.DiffRemoved
+		case git.ResolutionTheirs:
+			oursStyle, theirsStyle = m.theme.DiffRemoved, m.theme.DiffAdded
+		case git.ResolutionBoth:
+			oursStyle, theirsStyle = m.theme.DiffAdded, m.theme.DiffAdded
+		}
+
+		header := "<<<<<<< " + block.OursLabel + " (ours)"
+		if label := resolutionLabel(block.Resolution); label != "" {
+			header += " → " + label
+		}
+		writeLine(gutter, m.theme.DiffHunk.Render(header))
+		for _, line := range block.Ours {
+			writeLine(gutter, oursStyle.Render(line))
+		}
+		if block.Base != nil {
+			writeLine(gutter, m.theme.DiffHunk.Render("||||||| "+block.BaseLabel+" (base)"))
+			for _, line := range block.Base {
+				writeLine(gutter, m.theme.GitUntracked.Render(line))
+			}
+		}
+		writeLine(gutter, m.theme.DiffHunk.Render("======="))
+		for _, line := range block.Theirs {
+			writeLine(gutter, theirsStyle.Render(line))
+		}
+		writeLine(gutter, m.theme.DiffHunk.Render(">>>>>>> "+block.TheirsLabel+" (theirs)"))
+	}
+
+	content := strings.TrimRight(builder.String(), "\n")
+	m.panels[MainPanel].content = content
+	m.panels[MainPanel].viewport.SetContent(content)
+	m.panels[MainPanel].viewport.SetYOffset(selectedOffset)
+}
+
+// resolutionLabel returns a short description of a block resolution.
+func resolutionLabel(resolution git.ConflictResolution) string {
+	switch resolution {
+	case git.ResolutionOurs:
+		return "ours"
+	case git.ResolutionTheirs:
+		return "theirs"
+	case git.ResolutionBoth:
+		return "both"
+	}
+	return ""
+}
+
+// conflictTitle returns the Main panel title while the conflict view is shown.
+func (m Model) conflictTitle() string {
+	if m.conflicts.file == nil {
+		return fmt.Sprintf("Conflicts: %s", m.conflicts.path)
+	}
+	blocks := m.conflicts.file.Blocks()
+	if len(blocks) == 0 {
+		return fmt.Sprintf("Conflicts: %s (no conflict markers)", m.conflicts.path)
+	}
+	unresolved := 0
+	for _, block := range blocks {
+		if block.Resolution == git.ResolutionNone {
+			unresolved++
+		}
+	}
+	return fmt.Sprintf("Conflicts: %s (block %d/%d, %d unresolved)", m.conflicts.path, m.conflicts.block+1, len(blocks), unresolved)
+}
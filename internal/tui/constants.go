@@ -34,6 +34,27 @@ const (
 	titleBarHeight = 2
 	// statusPanelHeight is the fixed height for the status panel.
 	statusPanelHeight = 3
+	// statusBannerHeight is the height added to the status panel while an operation is in progress.
+	statusBannerHeight = 1
+
+	// --- Commit Log ---
+	// commitPageSize is the number of commits loaded into the Commits panel at a time.
+	commitPageSize = 300
+	// commitLoadMargin is how close the cursor gets to the last loaded line
+	// before the next page of commits is loaded.
+	commitLoadMargin = 20
+	// commitDateMinWidth is the width of the Commits panel from which the age of
+	// each commit is shown.
+	commitDateMinWidth = 50
+	// commitAuthorMinWidth is the width of the Commits panel from which author
+	// names are shown instead of their initials.
+	commitAuthorMinWidth = 70
+	// commitAuthorWidth is the width of the column of author names.
+	commitAuthorWidth = 14
+
+	// --- Workspace ---
+	// maxRecentRepos is the number of recently opened repositories that are remembered.
+	maxRecentRepos = 20
 
 	// --- Help View Styling ---
 	// helpTitleMargin is the left margin for the title in the help view.
@@ -47,17 +68,16 @@ const (
 	scrollThumbChar       = "▐"
 	graphNodeChar         = "○"
 	dirExpandedIcon       = "▼ "
+	dirCollapsedIcon      = "▶ "
+	hunkSelectedGutter    = "▌"
+	lineSelectedGutter    = "●"
+	copiedCommitMarker    = "◆"
 	repoRootNodeName      = "."
-	gitRenameDelimiter    = " -> "
 	initialContentLoading = "Loading..."
 
 	// --- File Watcher ---
 	// fileWatcherPollInterval is the debounce interval for repository file system events.
 	fileWatcherPollInterval = 500 * time.Millisecond
-
-	// --- Git Status Parsing ---
-	// porcelainStatusPrefixLength is the length of the status prefix in `git status --porcelain`.
-	porcelainStatusPrefixLength = 3
 )
 
 // --- Border Characters ---
@@ -0,0 +1,21 @@
+package tui
+
+import "github.com/gitxtui/gitx/internal/git"
+
+// errorHint returns advice on how to deal with the kind of error a git command
+// failed with, or an empty string if there is none.
+func errorHint(err error) string {
+	switch git.ErrorKindOf(err) {
+	case git.ErrorNotARepository:
+		return "Hint: open a repository from the repository switcher, or pass its path as in `gitx <path>`."
+	case git.ErrorConflict:
+		return "Hint: resolve the conflicts in the Files panel, then continue or abort."
+	case git.ErrorLockHeld:
+		return "Hint: another git process is running. If it crashed, remove the index.lock file in the .git directory."
+	case git.ErrorAuthFailed:
+		return "Hint: gitx cannot answer credential prompts. Set up a credential helper or an SSH key."
+	case git.ErrorNothingToCommit:
+		return "Hint: stage changes in the Files panel before committing."
+	}
+	return ""
+}
@@ -2,52 +2,36 @@ package tui
 
 import (
 	"fmt"
-	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/gitxtui/gitx/internal/git"
 )
 
 // Node represents a file or directory within the file tree structure.
 type Node struct {
-	name      string
-	status    string // Git status prefix (e.g., "M ", "??"), only for file nodes.
-	path      string // Full path relative to the repository root.
-	isRenamed bool
-	children  []*Node
+	name     string
+	path     string          // Full path relative to the repository root.
+	file     *git.FileStatus // Status of the file, nil for directory nodes.
+	children []*Node
 }
 
-// BuildTree parses the output of `git status --porcelain` to construct a file tree.
-func BuildTree(gitStatus string) *Node {
+// BuildTree constructs a file tree from the entries of `git status`.
+func BuildTree(files []git.FileStatus) *Node {
 	root := &Node{name: repoRootNodeName, path: "."}
 
-	lines := strings.Split(gitStatus, "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return root
-	}
-
-	for _, line := range lines {
-		if len(line) < porcelainStatusPrefixLength {
-			continue
-		}
-		status := line[:2]
-		fullPath := strings.TrimSpace(line[porcelainStatusPrefixLength:])
-		isRenamed := false
-
-		if status[0] == 'R' || status[0] == 'C' {
-			parts := strings.Split(fullPath, gitRenameDelimiter)
-			if len(parts) == 2 {
-				fullPath = parts[1]
-				isRenamed = true
-			}
-		}
+	for i := range files {
+		file := &files[i]
+		// Untracked directories are reported with a trailing slash.
+		fullPath := strings.TrimSuffix(file.Path, "/")
 
-		parts := strings.Split(fullPath, string(filepath.Separator))
+		parts := strings.Split(fullPath, "/")
 		currentNode := root
 		for i, part := range parts {
 			childNode := currentNode.findChild(part)
 			if childNode == nil {
 				// Construct path for the new node based on its parent
-				nodePath := filepath.Join(currentNode.path, part)
+				nodePath := currentNode.path + "/" + part
 				if currentNode.path == "." {
 					nodePath = part
 				}
@@ -57,9 +41,8 @@ func BuildTree(gitStatus string) *Node {
 			currentNode = childNode
 
 			if i == len(parts)-1 { // Leaf node (file)
-				currentNode.status = status
-				currentNode.path = fullPath // Overwrite with the full path from git
-				currentNode.isRenamed = isRenamed
+				currentNode.file = file
+				currentNode.path = file.Path // Overwrite with the full path from git
 			}
 		}
 	}
@@ -69,11 +52,46 @@ func BuildTree(gitStatus string) *Node {
 	return root
 }
 
+// status returns the two-letter status code of a file node, or "" for directories.
+func (n *Node) status() string {
+	if n.file == nil {
+		return ""
+	}
+	return n.file.Code()
+}
+
+// hasUnstagedChanges reports whether the node, or any file below it, has changes
+// that are not staged yet.
+func (n *Node) hasUnstagedChanges() bool {
+	if n.file != nil {
+		return n.file.HasUnstagedChanges()
+	}
+	for _, child := range n.children {
+		if child.hasUnstagedChanges() {
+			return true
+		}
+	}
+	return false
+}
+
 // Render traverses the tree and returns a slice of formatted strings for display.
 func (n *Node) Render(theme Theme) []string {
 	return n.renderRecursive("", theme)
 }
 
+// Nodes returns the nodes of the tree below n in the same order as the lines
+// returned by Render.
+func (n *Node) Nodes() []*Node {
+	var nodes []*Node
+	for _, child := range n.children {
+		nodes = append(nodes, child)
+		if len(child.children) > 0 {
+			nodes = append(nodes, child.Nodes()...)
+		}
+	}
+	return nodes
+}
+
 // findChild searches for an immediate child node by name.
 func (n *Node) findChild(name string) *Node {
 	for _, child := range n.children {
@@ -123,7 +141,7 @@ func (n *Node) compact() {
 	// If a directory has only one child and that child is also a directory, merge them.
 	for len(n.children) == 1 && len(n.children[0].children) > 0 {
 		child := n.children[0]
-		n.name = filepath.Join(n.name, child.name)
+		n.name = n.name + "/" + child.name
 		n.path = child.path
 		n.children = child.children
 	}
@@ -137,16 +155,29 @@ func (n *Node) renderRecursive(prefix string, theme Theme) []string {
 		newPrefix := prefix + theme.Tree.Prefix
 
 		if len(child.children) > 0 { // It's a directory
-			displayName := dirExpandedIcon + child.name
-			lines = append(lines, fmt.Sprintf("%s\t\t%s\t%s", prefix, displayName, child.path))
+			displayName := dirExpandedIcon + sanitizeFileName(child.name)
+			lines = append(lines, fmt.Sprintf("%s\t\t%s", prefix, displayName))
 			lines = append(lines, child.renderRecursive(newPrefix, theme)...)
 		} else { // It's a file.
 			displayName := child.name
-			if child.isRenamed {
+			if child.file.IsRenamed() {
 				displayName = child.path
 			}
-			lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", prefix, child.status, displayName, child.path))
+			if sub := child.file.Submodule; sub.IsSubmodule {
+				label := "submodule"
+				if summary := sub.Summary(); summary != "" {
+					label += ": " + summary
+				}
+				displayName += " (" + label + ")"
+			}
+			lines = append(lines, fmt.Sprintf("%s\t%s\t%s", prefix, child.status(), sanitizeFileName(displayName)))
 		}
 	}
 	return lines
 }
+
+// sanitizeFileName replaces characters that would break the line-based
+// rendering of the file tree.
+func sanitizeFileName(name string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(name)
+}
@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// Glyphs of the commit graph. Each lane is drawn in a column of its own,
+// followed by a column for the horizontal edges between lanes.
+const (
+	graphCommit     = "●"
+	graphMerge      = "◎"
+	graphVertical   = "│"
+	graphHorizontal = "─"
+	graphCrossing   = "┼"
+	graphMergeLeft  = "╯" // A lane that ends in a commit to its left.
+	graphMergeRight = "╰" // A lane that ends in a commit to its right.
+	graphForkRight  = "╮" // A lane that starts at a commit to its left.
+	graphForkLeft   = "╭" // A lane that starts at a commit to its right.
+	graphJoinLeft   = "┤" // A lane that a commit to its left also continues in.
+	graphJoinRight  = "├" // A lane that a commit to its right also continues in.
+)
+
+// graphJunctions holds the glyph an edge ends in when another edge passes it.
+var graphJunctions = map[string]string{
+	graphMergeLeft:  "┴",
+	graphMergeRight: "┴",
+	graphForkRight:  "┬",
+	graphForkLeft:   "┬",
+	graphJoinLeft:   graphCrossing,
+	graphJoinRight:  graphCrossing,
+}
+
+// graphCell is a glyph of the commit graph and the lane color it is drawn in.
+type graphCell struct {
+	glyph string
+	color int // An index into Theme.GraphColors, or -1 for blank cells.
+}
+
+// graphRow is the part of the commit graph on the line of a commit.
+type graphRow []graphCell
+
+// graphLane is a line of descent in the commit graph: it runs down from a
+// commit to the parent it is waiting for.
+type graphLane struct {
+	hash  string // The commit the lane is waiting for, empty if the lane is free.
+	color int
+}
+
+// graphLayout is the state of the commit graph below the commits laid out so
+// far: the lanes running down to the commits still to come.
+type graphLayout struct {
+	lanes     []graphLane
+	nextColor int
+}
+
+// buildGraph lays out the commit graph of commits, which must list no parent
+// before its children, and returns a row for each commit. A lane keeps its
+// color down the first parents of a branch.
+func buildGraph(commits []git.CommitLog) []graphRow {
+	var layout graphLayout
+	return layout.add(commits)
+}
+
+// add lays out the commits that follow the ones laid out so far, e.g. the next
+// page of the commit log, and returns a row for each commit. The layout of a
+// commit only depends on the commits before it, so laying out a list of
+// commits in pages gives the same rows as laying it out at once.
+func (l *graphLayout) add(commits []git.CommitLog) []graphRow {
+	// The lanes are copied, as copies of the model share the layout.
+	lanes := slices.Clone(l.lanes)
+	nextColor := l.nextColor
+	newLane := func(hash string) int {
+		lane := graphLane{hash: hash, color: nextColor}
+		nextColor++
+		for i := range lanes {
+			if lanes[i].hash == "" {
+				lanes[i] = lane
+				return i
+			}
+		}
+		lanes = append(lanes, lane)
+		return len(lanes) - 1
+	}
+
+	rows := make([]graphRow, len(commits))
+	for n, commit := range commits {
+		// The commit continues the first lane waiting for it, or starts a new
+		// one if it is the head of a branch.
+		col := -1
+		for i, lane := range lanes {
+			if lane.hash == commit.Hash {
+				col = i
+				break
+			}
+		}
+		if col < 0 {
+			col = newLane(commit.Hash)
+		}
+
+		// Each edge runs horizontally between the commit and another lane.
+		type edge struct {
+			lane  int
+			glyph string
+			color int
+		}
+		var edges []edge
+
+		// Other lanes waiting for the commit end in it. They are freed once the
+		// row is drawn, so no parent takes them over on the same row.
+		var merged []int
+		for i, lane := range lanes {
+			if i != col && lane.hash == commit.Hash {
+				edges = append(edges, edge{i, pick(i > col, graphMergeLeft, graphMergeRight), lane.color})
+				merged = append(merged, i)
+			}
+		}
+
+		// The lane of the commit continues to its first parent, and each other
+		// parent joins the lane already waiting for it or starts a new one.
+		ended := len(commit.Parents) == 0
+		var otherParents []string
+		if !ended {
+			lanes[col].hash = commit.Parents[0]
+			otherParents = commit.Parents[1:]
+		}
+		for _, parent := range otherParents {
+			joined := false
+			for i, lane := range lanes {
+				if i != col && lane.hash == parent {
+					edges = append(edges, edge{i, pick(i > col, graphJoinLeft, graphJoinRight), lane.color})
+					joined = true
+					break
+				}
+			}
+			if !joined {
+				i := newLane(parent)
+				edges = append(edges, edge{i, pick(i > col, graphForkRight, graphForkLeft), lanes[i].color})
+			}
+		}
+
+		row := make(graphRow, 2*len(lanes))
+		for i := range row {
+			row[i] = graphCell{glyph: " ", color: -1}
+		}
+		for i, lane := range lanes {
+			if lane.hash != "" {
+				row[2*i] = graphCell{glyph: graphVertical, color: lane.color}
+			}
+		}
+		// The farthest edges are drawn first, so nearer ones draw over the
+		// part they share, and edges passing an end of a nearer one turn it
+		// into a junction.
+		sort.SliceStable(edges, func(a, b int) bool {
+			return abs(edges[a].lane-col) > abs(edges[b].lane-col)
+		})
+		for k, e := range edges {
+			step := 1
+			if e.lane < col {
+				step = -1
+			}
+			for x := 2*col + step; x != 2*e.lane; x += step {
+				glyph := graphHorizontal
+				if row[x].glyph == graphVertical || row[x].glyph == graphCrossing {
+					glyph = graphCrossing
+				}
+				row[x] = graphCell{glyph: glyph, color: e.color}
+			}
+			glyph := e.glyph
+			for _, farther := range edges[:k] {
+				if (farther.lane > col) == (e.lane > col) {
+					glyph = graphJunctions[glyph]
+					break
+				}
+			}
+			row[2*e.lane] = graphCell{glyph: glyph, color: e.color}
+		}
+		node := graphCommit
+		if len(commit.Parents) > 1 {
+			node = graphMerge
+		}
+		row[2*col] = graphCell{glyph: node, color: lanes[col].color}
+		if ended {
+			lanes[col].hash = ""
+		}
+		for _, i := range merged {
+			lanes[i].hash = ""
+		}
+
+		// Free lanes at the end take up no room.
+		for len(lanes) > 0 && lanes[len(lanes)-1].hash == "" {
+			lanes = lanes[:len(lanes)-1]
+		}
+		rows[n] = row.trim()
+	}
+	l.lanes, l.nextColor = lanes, nextColor
+	return rows
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// pick returns a if cond is true, or b otherwise.
+func pick(cond bool, a, b string) string {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// trim removes the blank cells at the end of the row.
+func (r graphRow) trim() graphRow {
+	for len(r) > 0 && r[len(r)-1].color < 0 {
+		r = r[:len(r)-1]
+	}
+	return r
+}
+
+// String returns the glyphs of the row without colors.
+func (r graphRow) String() string {
+	var b strings.Builder
+	for _, cell := range r {
+		b.WriteString(cell.glyph)
+	}
+	return b.String()
+}
+
+// render draws the row in the graph colors of theme on top of base, e.g. the
+// style of the selected line.
+func (r graphRow) render(theme Theme, base lipgloss.Style) string {
+	var b strings.Builder
+	for _, cell := range r {
+		style := lipgloss.NewStyle().Inherit(base)
+		if cell.color >= 0 {
+			style = theme.GraphEdge.Inherit(base)
+			if len(theme.GraphColors) > 0 {
+				style = theme.GraphColors[cell.color%len(theme.GraphColors)].Inherit(base)
+			}
+		}
+		b.WriteString(style.Render(cell.glyph))
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// fileHistoryState holds the file the Commits panel is scoped to, if any.
+type fileHistoryState struct {
+	path  string
+	paths map[string]string // The path of the file in each listed commit, as it may have been renamed.
+}
+
+// fileHistoryUpdatedMsg is sent when the history of a file has been fetched.
+type fileHistoryUpdatedMsg struct {
+	path string
+	logs []git.CommitLog
+	err  error
+}
+
+// fileHistoryRequestedMsg is sent when the path of a file to show the history
+// of has been entered.
+type fileHistoryRequestedMsg struct {
+	path string
+}
+
+// showFileHistory scopes the Commits panel to the history of a file and moves
+// focus to it.
+func (m *Model) showFileHistory(path string) tea.Cmd {
+	m.fileHistory = fileHistoryState{path: path}
+	m.panels[CommitsPanel].tab = defaultTab
+	m.panels[CommitsPanel].cursor = 0
+	m.panels[CommitsPanel].lines = nil
+	m.panels[CommitsPanel].viewport.GotoTop()
+	m.focusedPanel = CommitsPanel
+	m.activeSourcePanel = CommitsPanel
+	m.mainView = mainViewDiff
+	*m = m.recalculateLayout()
+	return m.fetchPanelContent(CommitsPanel)
+}
+
+// clearFileHistory shows the full commit log in the Commits panel again.
+func (m *Model) clearFileHistory() tea.Cmd {
+	m.fileHistory = fileHistoryState{}
+	m.panels[CommitsPanel].cursor = 0
+	m.panels[CommitsPanel].viewport.GotoTop()
+	return m.fetchPanelContent(CommitsPanel)
+}
+
+// promptFileHistory asks for the path of a tracked file to show the history of.
+func (m *Model) promptFileHistory() tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = "Show History of File"
+	m.textInput.SetValue(m.fileHistory.path)
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg { return fileHistoryRequestedMsg{path: input} }
+	}
+	return nil
+}
+
+// handleFileHistoryUpdatedMsg renders the history of a file into the Commits
+// panel.
+func (m Model) handleFileHistoryUpdatedMsg(msg fileHistoryUpdatedMsg) (Model, tea.Cmd) {
+	if msg.path != m.fileHistory.path || m.rebaseTodo.active || m.panels[CommitsPanel].tab != defaultTab {
+		return m, nil // Stale message.
+	}
+
+	paths := make(map[string]string, len(msg.logs))
+	lines := make([]string, len(msg.logs))
+	for i, log := range msg.logs {
+		paths[log.SHA] = log.Path
+		lines[i] = fmt.Sprintf("%s\t%s\t%s\t%s", log.Graph, log.SHA, log.AuthorInitials, log.Subject)
+	}
+	switch {
+	case msg.err != nil:
+		lines = []string{"Error: " + msg.err.Error()}
+	case len(lines) == 0:
+		lines = []string{fmt.Sprintf("No commits changed %s.", msg.path)}
+	}
+
+	m.fileHistory.paths = paths
+	content := strings.Join(lines, "\n")
+	m.panels[CommitsPanel].lines = lines
+	m.panels[CommitsPanel].content = content
+	m.panels[CommitsPanel].viewport.SetContent(content)
+	if m.panels[CommitsPanel].cursor >= len(lines) {
+		m.panels[CommitsPanel].cursor = len(lines) - 1
+	}
+	return m, m.updateMainPanel()
+}
+
+// showCommit shows a commit in the Main panel, limited to the changes to the
+// file if the Commits panel is scoped to the history of a file.
+func (m Model) showCommit(sha string) (string, error) {
+	if m.fileHistory.path == "" {
+		return m.git.ShowCommit(sha)
+	}
+	path, ok := m.fileHistory.paths[sha]
+	if !ok {
+		path = m.fileHistory.path
+	}
+	return m.git.ShowCommitFile(sha, path)
+}
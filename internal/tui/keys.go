@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
 )
 
 // KeyMap stores keybindings by action name.
@@ -17,37 +18,98 @@ type HelpSection struct {
 }
 
 var keybindingDescriptions = map[string]string{
-	"quit":              "quit",
-	"escape":            "cancel",
-	"toggle_help":       "toggle help",
-	"switch_theme":      "switch theme",
-	"focus_next":        "Focus Next Window",
-	"focus_prev":        "Focus Previous Window",
-	"focus_main":        "Focus Main Window",
-	"focus_status":      "Focus Status Window",
-	"focus_files":       "Focus Files Window",
-	"focus_branches":    "Focus Branches Window",
-	"focus_commits":     "Focus Commits Window",
-	"focus_stash":       "Focus Stash Window",
-	"focus_command_log": "Focus Command log Window",
-	"up":                "up",
-	"down":              "down",
-	"stage_item":        "Stage Item",
-	"stage_all":         "Stage All",
-	"discard":           "Discard",
-	"stash":             "Stash",
-	"stash_all":         "Stash all",
-	"commit":            "Commit",
-	"checkout":          "Checkout",
-	"new_branch":        "New Branch",
-	"delete_branch":     "Delete",
-	"rename_branch":     "Rename",
-	"amend_commit":      "Amend",
-	"revert":            "Revert",
-	"reset_to_commit":   "Reset to Commit",
-	"stash_apply":       "Apply",
-	"stash_pop":         "Pop",
-	"stash_drop":        "Drop",
+	"quit":               "quit",
+	"escape":             "cancel",
+	"toggle_help":        "toggle help",
+	"switch_theme":       "switch theme",
+	"focus_next":         "Focus Next Window",
+	"focus_prev":         "Focus Previous Window",
+	"focus_main":         "Focus Main Window",
+	"focus_status":       "Focus Status Window",
+	"focus_files":        "Focus Files Window",
+	"focus_branches":     "Focus Branches Window",
+	"focus_commits":      "Focus Commits Window",
+	"focus_stash":        "Focus Stash Window",
+	"focus_command_log":  "Focus Command log Window",
+	"next_tab":           "Next Tab",
+	"prev_tab":           "Previous Tab",
+	"up":                 "up",
+	"down":               "down",
+	"stage_item":         "Stage Item",
+	"stage_all":          "Stage All",
+	"discard":            "Discard",
+	"stash":              "Stash",
+	"stash_all":          "Stash all",
+	"commit":             "Commit",
+	"checkout":           "Checkout",
+	"new_branch":         "New Branch",
+	"delete_branch":      "Delete",
+	"rename_branch":      "Rename",
+	"set_upstream":       "Set upstream",
+	"unset_upstream":     "Unset upstream",
+	"amend_commit":       "Amend",
+	"revert":             "Revert",
+	"reset_to_commit":    "Reset to Commit",
+	"stash_apply":        "Apply",
+	"stash_pop":          "Pop",
+	"stash_drop":         "Drop",
+	"stage_hunks":        "Stage Hunks",
+	"blame":              "Blame",
+	"file_history":       "File history",
+	"goto_commit":        "Go to commit",
+	"blame_parent":       "Blame parent",
+	"stage_hunk":         "Stage/Unstage Hunk",
+	"toggle_staged":      "Toggle Staged/Unstaged",
+	"toggle_line_mode":   "Select Lines",
+	"select_line":        "Toggle Line",
+	"apply_lines":        "Stage/Unstage Lines",
+	"interactive_rebase": "Interactive Rebase",
+	"rebase_pick":        "Pick",
+	"rebase_reword":      "Reword",
+	"rebase_edit":        "Edit",
+	"rebase_squash":      "Squash",
+	"rebase_fixup":       "Fixup",
+	"rebase_drop":        "Drop",
+	"rebase_move_down":   "Move Down",
+	"rebase_move_up":     "Move Up",
+	"rebase_start":       "Start Rebase",
+	"continue_operation": "Continue",
+	"skip_operation":     "Skip",
+	"abort_operation":    "Abort",
+	"copy_commit":        "Copy Commit",
+	"paste_commits":      "Paste Commits",
+	"bisect_bad":         "Bisect: Mark Bad",
+	"bisect_good":        "Bisect: Mark Good",
+	"bisect_run":         "Bisect: Run Command",
+	"resolve_ours":       "Pick Ours",
+	"resolve_theirs":     "Pick Theirs",
+	"resolve_both":       "Pick Both",
+	"write_resolution":   "Write & Stage",
+	"checkout_ours":      "Use Ours for File",
+	"checkout_theirs":    "Use Theirs for File",
+	"add_remote":         "Add Remote",
+	"remove_remote":      "Remove Remote",
+	"rename_remote":      "Rename Remote",
+	"edit_remote_url":    "Edit URL",
+	"new_tag":            "New Tag",
+	"delete_tag":         "Delete Tag",
+	"push_tag":           "Push Tag",
+	"delete_remote_tag":  "Delete Tag on Remote",
+	"push":               "Push",
+	"pull":               "Pull",
+	"fetch":              "Fetch",
+	"cancel":             "Cancel Running Commands",
+	"switch_repo":        "Switch Repository",
+	"new_worktree":       "New Worktree",
+	"remove_worktree":    "Remove Worktree",
+	"lock_worktree":      "Lock/Unlock Worktree",
+	"prune_worktrees":    "Prune Worktrees",
+	"init_submodule":     "Init Submodule",
+	"update_submodule":   "Update Submodule",
+	"sync_submodule":     "Sync Submodule URL",
+	"open_repo_path":     "Open Repository at Path",
+	"undo":               "Undo",
+	"redo":               "Redo",
 }
 
 func keySpec(keys ...string) string {
@@ -57,37 +119,98 @@ func keySpec(keys ...string) string {
 // DefaultKeybindings returns default keybindings for each action.
 func DefaultKeybindings() map[string]string {
 	return map[string]string{
-		"quit":              keySpec("q", "ctrl+c"),
-		"escape":            keySpec("esc"),
-		"toggle_help":       keySpec("?"),
-		"switch_theme":      keySpec("ctrl+t"),
-		"focus_next":        keySpec("tab"),
-		"focus_prev":        keySpec("shift+tab"),
-		"focus_main":        keySpec("0"),
-		"focus_status":      keySpec("1"),
-		"focus_files":       keySpec("2"),
-		"focus_branches":    keySpec("3"),
-		"focus_commits":     keySpec("4"),
-		"focus_stash":       keySpec("5"),
-		"focus_command_log": keySpec("6"),
-		"up":                keySpec("k", "up"),
-		"down":              keySpec("j", "down"),
-		"stage_item":        keySpec("a"),
-		"stage_all":         keySpec("space"),
-		"discard":           keySpec("d"),
-		"stash":             keySpec("s"),
-		"stash_all":         keySpec("S"),
-		"commit":            keySpec("c"),
-		"checkout":          keySpec("enter"),
-		"new_branch":        keySpec("n"),
-		"delete_branch":     keySpec("d"),
-		"rename_branch":     keySpec("r"),
-		"amend_commit":      keySpec("A"),
-		"revert":            keySpec("v"),
-		"reset_to_commit":   keySpec("R"),
-		"stash_apply":       keySpec("a"),
-		"stash_pop":         keySpec("p"),
-		"stash_drop":        keySpec("d"),
+		"quit":               keySpec("q", "ctrl+c"),
+		"escape":             keySpec("esc"),
+		"toggle_help":        keySpec("?"),
+		"switch_theme":       keySpec("ctrl+t"),
+		"focus_next":         keySpec("tab"),
+		"focus_prev":         keySpec("shift+tab"),
+		"focus_main":         keySpec("0"),
+		"focus_status":       keySpec("1"),
+		"focus_files":        keySpec("2"),
+		"focus_branches":     keySpec("3"),
+		"focus_commits":      keySpec("4"),
+		"focus_stash":        keySpec("5"),
+		"focus_command_log":  keySpec("6"),
+		"next_tab":           keySpec("]"),
+		"prev_tab":           keySpec("["),
+		"up":                 keySpec("k", "up"),
+		"down":               keySpec("j", "down"),
+		"stage_item":         keySpec("a"),
+		"stage_all":          keySpec("space"),
+		"discard":            keySpec("d"),
+		"stash":              keySpec("s"),
+		"stash_all":          keySpec("S"),
+		"commit":             keySpec("c"),
+		"checkout":           keySpec("enter"),
+		"new_branch":         keySpec("n"),
+		"delete_branch":      keySpec("d"),
+		"rename_branch":      keySpec("r"),
+		"set_upstream":       keySpec("u"),
+		"unset_upstream":     keySpec("U"),
+		"amend_commit":       keySpec("A"),
+		"revert":             keySpec("v"),
+		"reset_to_commit":    keySpec("R"),
+		"stash_apply":        keySpec("a"),
+		"stash_pop":          keySpec("p"),
+		"stash_drop":         keySpec("d"),
+		"stage_hunks":        keySpec("enter"),
+		"blame":              keySpec("b"),
+		"file_history":       keySpec("h"),
+		"goto_commit":        keySpec("enter"),
+		"blame_parent":       keySpec("p"),
+		"stage_hunk":         keySpec("space"),
+		"toggle_staged":      keySpec("t"),
+		"toggle_line_mode":   keySpec("v"),
+		"select_line":        keySpec("space"),
+		"apply_lines":        keySpec("enter"),
+		"interactive_rebase": keySpec("i"),
+		"rebase_pick":        keySpec("p"),
+		"rebase_reword":      keySpec("r"),
+		"rebase_edit":        keySpec("e"),
+		"rebase_squash":      keySpec("s"),
+		"rebase_fixup":       keySpec("f"),
+		"rebase_drop":        keySpec("d"),
+		"rebase_move_down":   keySpec("ctrl+j"),
+		"rebase_move_up":     keySpec("ctrl+k"),
+		"rebase_start":       keySpec("enter"),
+		"continue_operation": keySpec("c"),
+		"skip_operation":     keySpec("s"),
+		"abort_operation":    keySpec("X"),
+		"copy_commit":        keySpec("C"),
+		"paste_commits":      keySpec("V"),
+		"bisect_bad":         keySpec("b"),
+		"bisect_good":        keySpec("g"),
+		"bisect_run":         keySpec("B"),
+		"resolve_ours":       keySpec("o"),
+		"resolve_theirs":     keySpec("t"),
+		"resolve_both":       keySpec("b"),
+		"write_resolution":   keySpec("enter"),
+		"checkout_ours":      keySpec("O"),
+		"checkout_theirs":    keySpec("T"),
+		"add_remote":         keySpec("n"),
+		"remove_remote":      keySpec("d"),
+		"rename_remote":      keySpec("r"),
+		"edit_remote_url":    keySpec("e"),
+		"new_tag":            keySpec("t"),
+		"delete_tag":         keySpec("d"),
+		"push_tag":           keySpec("P"),
+		"delete_remote_tag":  keySpec("D"),
+		"push":               keySpec("P"),
+		"pull":               keySpec("p"),
+		"fetch":              keySpec("f"),
+		"cancel":             keySpec("ctrl+x"),
+		"switch_repo":        keySpec("ctrl+r"),
+		"new_worktree":       keySpec("n"),
+		"remove_worktree":    keySpec("d"),
+		"lock_worktree":      keySpec("l"),
+		"prune_worktrees":    keySpec("x"),
+		"init_submodule":     keySpec("i"),
+		"update_submodule":   keySpec("u"),
+		"sync_submodule":     keySpec("s"),
+		"open_repo_path":     keySpec("o"),
+		"undo":               keySpec("z"),
+		"redo":               keySpec("Z"),
 	}
 }
 
@@ -140,6 +263,19 @@ func parseConfiguredKeys(configured string) ([]string, bool) {
 	return keys, len(keys) > 0
 }
 
+// matchKeys converts configured key names into the strings bubbletea reports
+// for them; the space bar is reported as " " rather than "space".
+func matchKeys(keys []string) []string {
+	result := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "space" {
+			k = " "
+		}
+		result[i] = k
+	}
+	return result
+}
+
 func helpLabel(keys []string) string {
 	return strings.Join(keys, "/")
 }
@@ -155,7 +291,7 @@ func (k KeyMap) binding(action string) key.Binding {
 	}
 	desc := keybindingDescriptions[action]
 	return key.NewBinding(
-		key.WithKeys(resolvedKeys...),
+		key.WithKeys(matchKeys(resolvedKeys)...),
 		key.WithHelp(helpLabel(resolvedKeys), desc),
 	)
 }
@@ -166,7 +302,7 @@ func Matches(msg tea.KeyMsg, spec string) bool {
 	if !ok {
 		return false
 	}
-	return key.Matches(msg, key.NewBinding(key.WithKeys(resolvedKeys...)))
+	return key.Matches(msg, key.NewBinding(key.WithKeys(matchKeys(resolvedKeys)...)))
 }
 
 func (k KeyMap) bindings(actions ...string) []key.Binding {
@@ -184,13 +320,33 @@ func (k KeyMap) FullHelp() []HelpSection {
 		{Title: "Navigation", Bindings: k.bindings(
 			"focus_next", "focus_prev", "focus_main", "focus_status",
 			"focus_files", "focus_branches", "focus_commits", "focus_stash",
-			"focus_command_log", "up", "down",
+			"focus_command_log", "next_tab", "prev_tab", "up", "down",
+		)},
+		{Title: "Files", Bindings: k.bindings("commit", "stash", "stash_all", "stage_item", "stage_all", "discard", "stage_hunks", "blame", "file_history")},
+		{Title: "Blame", Bindings: k.bindings("goto_commit", "blame_parent")},
+		{Title: "Staging", Bindings: k.bindings("stage_hunk", "toggle_staged", "toggle_line_mode", "select_line", "apply_lines")},
+		{Title: "Conflicts", Bindings: k.bindings(
+			"resolve_ours", "resolve_theirs", "resolve_both", "write_resolution", "checkout_ours", "checkout_theirs",
 		)},
-		{Title: "Files", Bindings: k.bindings("commit", "stash", "stash_all", "stage_item", "stage_all", "discard")},
-		{Title: "Branches", Bindings: k.bindings("checkout", "new_branch", "delete_branch", "rename_branch")},
-		{Title: "Commits", Bindings: k.bindings("amend_commit", "revert", "reset_to_commit")},
+		{Title: "Branches", Bindings: k.bindings("checkout", "new_branch", "delete_branch", "rename_branch", "set_upstream", "unset_upstream")},
+		{Title: "Sync", Bindings: k.bindings("push", "pull", "fetch")},
+		{Title: "Remotes", Bindings: k.bindings("fetch", "add_remote", "remove_remote", "rename_remote", "edit_remote_url")},
+		{Title: "Tags", Bindings: k.bindings("new_tag", "delete_tag", "push_tag", "delete_remote_tag")},
+		{Title: "Commits", Bindings: k.bindings(
+			"amend_commit", "revert", "reset_to_commit", "interactive_rebase", "copy_commit", "paste_commits", "new_tag", "file_history",
+		)},
+		{Title: "Reflog", Bindings: k.bindings("undo", "redo", "reset_to_commit", "copy_commit")},
+		{Title: "Interactive Rebase", Bindings: k.bindings(
+			"rebase_pick", "rebase_reword", "rebase_edit", "rebase_squash", "rebase_fixup",
+			"rebase_drop", "rebase_move_down", "rebase_move_up", "rebase_start",
+		)},
+		{Title: "Bisect", Bindings: k.bindings("bisect_bad", "bisect_good", "bisect_run")},
+		{Title: "Operations", Bindings: k.bindings("continue_operation", "skip_operation", "abort_operation")},
 		{Title: "Stash", Bindings: k.bindings("stash_apply", "stash_pop", "stash_drop")},
-		{Title: "Misc", Bindings: k.bindings("switch_theme", "toggle_help", "escape", "quit")},
+		{Title: "Workspace", Bindings: k.bindings("switch_repo", "open_repo_path")},
+		{Title: "Worktrees", Bindings: k.bindings("checkout", "new_worktree", "remove_worktree", "lock_worktree", "prune_worktrees")},
+		{Title: "Submodules", Bindings: k.bindings("checkout", "init_submodule", "update_submodule", "sync_submodule")},
+		{Title: "Misc", Bindings: k.bindings("switch_theme", "toggle_help", "escape", "cancel", "quit")},
 	}
 }
 
@@ -206,19 +362,100 @@ func (k KeyMap) HelpViewHelp() []key.Binding {
 
 // FilesPanelHelp returns a slice of key.Binding containing help for keybindings related to Files Panel.
 func (k KeyMap) FilesPanelHelp() []key.Binding {
-	help := k.bindings("commit", "stash", "discard", "stage_item")
+	help := k.bindings("commit", "stash", "discard", "stage_item", "stage_hunks", "blame", "file_history")
+	return append(help, k.ShortHelp()...)
+}
+
+// WorktreesHelp returns a slice of key.Binding for the Worktrees tab of the Files Panel.
+func (k KeyMap) WorktreesHelp() []key.Binding {
+	help := k.bindings("checkout", "new_worktree", "remove_worktree", "lock_worktree", "prune_worktrees", "next_tab")
 	return append(help, k.ShortHelp()...)
 }
 
+// SubmodulesHelp returns a slice of key.Binding for the Submodules tab of the Files Panel.
+func (k KeyMap) SubmodulesHelp() []key.Binding {
+	help := k.bindings("checkout", "init_submodule", "update_submodule", "sync_submodule", "next_tab")
+	return append(help, k.ShortHelp()...)
+}
+
+// BlameViewHelp returns a slice of key.Binding for the blame view in the Main Panel.
+func (k KeyMap) BlameViewHelp() []key.Binding {
+	help := k.bindings("goto_commit", "blame_parent", "escape")
+	return append(help, k.bindings("toggle_help", "quit")...)
+}
+
+// StagingViewHelp returns a slice of key.Binding for the hunk staging view in the Main Panel.
+func (k KeyMap) StagingViewHelp() []key.Binding {
+	help := k.bindings("stage_hunk", "toggle_staged", "toggle_line_mode", "escape")
+	return append(help, k.bindings("toggle_help", "quit")...)
+}
+
+// StagingLinesHelp returns a slice of key.Binding for the line selection mode of the staging view.
+func (k KeyMap) StagingLinesHelp() []key.Binding {
+	help := k.bindings("select_line", "apply_lines", "escape")
+	return append(help, k.bindings("toggle_help", "quit")...)
+}
+
+// ConflictViewHelp returns a slice of key.Binding for the conflict resolution view in the Main Panel.
+func (k KeyMap) ConflictViewHelp() []key.Binding {
+	help := k.bindings("resolve_ours", "resolve_theirs", "resolve_both", "write_resolution", "checkout_ours", "checkout_theirs", "escape")
+	return append(help, k.bindings("toggle_help", "quit")...)
+}
+
 // BranchesPanelHelp returns a slice of key.Binding for the Branches Panel help bar.
 func (k KeyMap) BranchesPanelHelp() []key.Binding {
-	help := k.bindings("checkout", "new_branch", "delete_branch")
+	help := k.bindings("checkout", "new_branch", "delete_branch", "set_upstream", "push", "pull", "next_tab")
+	return append(help, k.ShortHelp()...)
+}
+
+// TagsHelp returns a slice of key.Binding for the Tags tab of the Branches Panel.
+func (k KeyMap) TagsHelp() []key.Binding {
+	help := k.bindings("new_tag", "delete_tag", "push_tag", "delete_remote_tag", "next_tab")
+	return append(help, k.ShortHelp()...)
+}
+
+// RemotesHelp returns a slice of key.Binding for the Remotes tab of the Branches Panel.
+func (k KeyMap) RemotesHelp() []key.Binding {
+	help := k.bindings("checkout", "fetch", "add_remote", "remove_remote", "rename_remote", "edit_remote_url", "next_tab")
 	return append(help, k.ShortHelp()...)
 }
 
 // CommitsPanelHelp returns a slice of key.Binding for the Commits Panel help bar.
 func (k KeyMap) CommitsPanelHelp() []key.Binding {
-	help := k.bindings("amend_commit", "revert", "reset_to_commit")
+	help := k.bindings("amend_commit", "revert", "reset_to_commit", "copy_commit", "paste_commits", "file_history", "next_tab")
+	return append(help, k.ShortHelp()...)
+}
+
+// ReflogHelp returns a slice of key.Binding for the Reflog tab of the Commits Panel.
+func (k KeyMap) ReflogHelp() []key.Binding {
+	help := k.bindings("undo", "redo", "reset_to_commit", "copy_commit", "next_tab")
+	return append(help, k.ShortHelp()...)
+}
+
+// RebaseTodoHelp returns a slice of key.Binding for the interactive rebase editor in the Commits Panel.
+func (k KeyMap) RebaseTodoHelp() []key.Binding {
+	help := k.bindings("rebase_pick", "rebase_reword", "rebase_squash", "rebase_fixup", "rebase_drop", "rebase_start", "escape")
+	return append(help, k.bindings("toggle_help", "quit")...)
+}
+
+// BisectHelp returns a slice of key.Binding for the Commits Panel while a bisect is in progress.
+func (k KeyMap) BisectHelp() []key.Binding {
+	help := k.bindings("bisect_good", "bisect_bad", "skip_operation", "bisect_run", "abort_operation")
+	return append(help, k.ShortHelp()...)
+}
+
+// OperationHelp returns a slice of key.Binding for the actions available for an operation in progress.
+func (k KeyMap) OperationHelp(op git.RepoOperation) []key.Binding {
+	var help []key.Binding
+	if op.CanContinue() {
+		help = append(help, k.binding("continue_operation"))
+	}
+	if op.CanSkip() {
+		help = append(help, k.binding("skip_operation"))
+	}
+	if op.CanAbort() {
+		help = append(help, k.binding("abort_operation"))
+	}
 	return append(help, k.ShortHelp()...)
 }
 
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
@@ -18,6 +20,23 @@ const (
 	modeInput
 	modeConfirm
 	modeCommit
+	modeRepoSwitcher
+)
+
+// mainView defines what the Main panel is currently showing.
+type mainView int
+
+const (
+	// mainViewDiff shows the details of the item selected in the active source panel.
+	mainViewDiff mainView = iota
+	// mainViewStaging shows the hunks of a file for partial staging.
+	mainViewStaging
+	// mainViewConflicts shows the conflict blocks of a file for resolution.
+	mainViewConflicts
+	// mainViewOutput shows the output of a long-running command.
+	mainViewOutput
+	// mainViewBlame shows the blame of a file.
+	mainViewBlame
 )
 
 // Model represents the state of the TUI.
@@ -36,8 +55,31 @@ type Model struct {
 	helpContent       string
 	showHelp          bool
 	git               *git.GitCommands
+	cancel            context.CancelFunc // Cancels the git commands of git.
+	cancelMainFetch   context.CancelFunc // Cancels the running fetch of the Main panel content.
 	repoName          string
+	repoRoot          string   // The root of the work tree, empty if gitx was started outside a repository.
+	parentRepos       []string // The repositories to return to from a submodule, innermost last.
+	workspace         workspaceConfig
+	repoSwitcher      repoSwitcherState
+	watchRepo         func(path string) // Points the file watcher at the work tree at path.
 	branchName        string
+	fileNodes         []*Node // Nodes of the Files panel, in the order of its lines.
+	mainView          mainView
+	staging           stagingState
+	conflicts         conflictState
+	output            outputState
+	blame             blameState
+	remotes           remotesState
+	tags              []*git.Tag       // The tags listed in the Tags tab.
+	worktrees         []*git.Worktree  // The worktrees listed in the Worktrees tab.
+	submodules        []*git.Submodule // The submodules listed in the Submodules tab.
+	rebaseTodo        rebaseTodoState
+	fileHistory       fileHistoryState
+	commitLog         commitLogState
+	repoState         git.RepoState
+	copiedCommits     []string // Commits marked for cherry-picking, in the order they are applied.
+	progress          progressState
 	// New fields for pop-ups
 	mode             appMode
 	promptTitle      string
@@ -47,13 +89,15 @@ type Model struct {
 	inputCallback    func(string) tea.Cmd
 	commitCallback   func(title, description string) tea.Cmd
 	confirmCallback  func(bool) tea.Cmd
+	heldMsgs         []tea.Msg // Messages held back until the pop-up shown is closed.
 	// New fields for command history
 	CommandHistory []string
 	keymap         KeyMap
 }
 
-// initialModel creates the initial state of the application.
-func initialModel() Model {
+// initialModel creates the initial state of the application for the repository
+// at repoPath, or the one in the current directory if repoPath is empty.
+func initialModel(repoPath string) Model {
 	themeNames := ThemeNames() //built-in themes load
 	cfg, err := load_config()
 	if err != nil {
@@ -76,8 +120,30 @@ func initialModel() Model {
 
 	themeNames = ThemeNames() // reload
 
-	gc := git.NewGitCommands()
+	gc := git.NewGitCommands().WithRepoPath(repoPath).WithTimeouts(cfg.Timeouts.gitTimeouts())
 	repoName, branchName, _ := gc.GetRepoInfo()
+	repoRoot, _ := gc.GetRepoRoot()
+	if repoRoot != "" {
+		// Paths in the panels are relative to the root, wherever gitx is started.
+		gc = gc.WithRepoPath(repoRoot)
+	}
+
+	m := newModel(gc, repoRoot, repoName, branchName)
+	m.theme = Themes[selectedThemeName]
+	m.themeNames = themeNames
+	m.themeIndex = indexOf(themeNames, selectedThemeName)
+	m.workspace = cfg.Workspace
+	m.keymap = keymap
+	if repoRoot == "" {
+		m.showRepoSwitcher(nil)
+	}
+	return m
+}
+
+// newModel creates the state of the application for the repository whose work
+// tree is at repoRoot, run by gc. It runs no git command, so it can be called
+// from Update; the caller sets up the theme, the keymap and the workspace.
+func newModel(gc *git.GitCommands, repoRoot, repoName, branchName string) Model {
 	initialContent := initialContentLoading
 
 	panels := make([]panel, totalPanels)
@@ -103,10 +169,7 @@ func initialModel() Model {
 	historyVP := viewport.New(0, 0)
 	historyVP.SetContent("Command history will appear here...")
 
-	return Model{
-		theme:             Themes[selectedThemeName],
-		themeNames:        themeNames,
-		themeIndex:        indexOf(themeNames, selectedThemeName),
+	m := Model{
 		focusedPanel:      StatusPanel,
 		activeSourcePanel: StatusPanel,
 		help:              help.New(),
@@ -114,14 +177,17 @@ func initialModel() Model {
 		showHelp:          false,
 		git:               gc,
 		repoName:          repoName,
+		repoRoot:          repoRoot,
 		branchName:        branchName,
 		panels:            panels,
 		mode:              modeNormal,
 		textInput:         ti,
 		descriptionInput:  ta,
+		commitLog:         commitLogState{limit: commitPageSize},
 		CommandHistory:    []string{},
-		keymap:            keymap,
 	}
+	m.bindContext()
+	return m
 }
 
 func indexOf(arr []string, val string) int {
@@ -135,8 +201,12 @@ func indexOf(arr []string, val string) int {
 
 // Init is the first command that is run when the program starts.
 func (m Model) Init() tea.Cmd {
+	if m.repoRoot == "" {
+		return nil // The repository switcher is shown until a repository is opened.
+	}
 	// fetch initial content for all panels.
 	return tea.Batch(
+		m.rememberRepo(),
 		m.fetchPanelContent(StatusPanel),
 		m.fetchPanelContent(FilesPanel),
 		m.fetchPanelContent(BranchesPanel),
@@ -156,11 +226,54 @@ func (m *Model) nextTheme() {
 // panelShortHelp returns a slice of key.Binding for the focused Panel.
 func (m *Model) panelShortHelp() []key.Binding {
 	switch m.focusedPanel {
+	case MainPanel:
+		if m.mainView == mainViewConflicts {
+			return m.keymap.ConflictViewHelp()
+		}
+		if m.mainView == mainViewBlame {
+			return m.keymap.BlameViewHelp()
+		}
+		if m.mainView == mainViewStaging {
+			if m.staging.lineMode {
+				return m.keymap.StagingLinesHelp()
+			}
+			return m.keymap.StagingViewHelp()
+		}
+		return m.keymap.ShortHelp()
+	case StatusPanel:
+		if m.hasOperationBanner() {
+			return m.keymap.OperationHelp(m.repoState.Operation)
+		}
+		return m.keymap.ShortHelp()
 	case FilesPanel:
+		switch m.panels[FilesPanel].tab {
+		case worktreesTab:
+			return m.keymap.WorktreesHelp()
+		case submodulesTab:
+			return m.keymap.SubmodulesHelp()
+		}
 		return m.keymap.FilesPanelHelp()
 	case BranchesPanel:
+		switch m.panels[BranchesPanel].tab {
+		case remotesTab:
+			return m.keymap.RemotesHelp()
+		case tagsTab:
+			return m.keymap.TagsHelp()
+		}
 		return m.keymap.BranchesPanelHelp()
 	case CommitsPanel:
+		if m.rebaseTodo.active {
+			return m.keymap.RebaseTodoHelp()
+		}
+		if m.panels[CommitsPanel].tab == reflogTab {
+			return m.keymap.ReflogHelp()
+		}
+		if m.isBisecting() {
+			return m.keymap.BisectHelp()
+		}
+		if op := m.repoState.Operation; op == git.OperationRebase || op == git.OperationCherryPick {
+			return m.keymap.OperationHelp(op)
+		}
 		return m.keymap.CommitsPanelHelp()
 	case StashPanel:
 		return m.keymap.StashPanelHelp()
@@ -1,14 +1,22 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gitxtui/gitx/internal/git"
 	zone "github.com/lrstanley/bubblezone"
 )
 
@@ -17,7 +25,7 @@ type testModel struct {
 }
 
 func TestModel_InitialPanels(t *testing.T) {
-	m := initialModel()
+	m := initialModel("")
 
 	if len(m.panels) != int(totalPanels) {
 		t.Fatalf("expected %d panels, but got %d", totalPanels, len(m.panels))
@@ -155,7 +163,7 @@ func TestModel_KeyFocus(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			m := initialModel()
+			m := initialModel("")
 			m.focusedPanel = tc.initialPanel
 			keyMsg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.key)}
 			if tc.key == "tab" {
@@ -172,7 +180,7 @@ func TestModel_KeyFocus(t *testing.T) {
 }
 
 func TestModel_contextualHelp(t *testing.T) {
-	m := initialModel()
+	m := initialModel("")
 	t.Run("Files Panel Help", func(t *testing.T) {
 		m.focusedPanel = FilesPanel
 		gotKeys := m.panelShortHelp()
@@ -219,7 +227,7 @@ func TestKeyMapFromConfig_InvalidValueUsesDefault(t *testing.T) {
 }
 
 func TestModel_HelpToggle(t *testing.T) {
-	m := initialModel()
+	m := initialModel("")
 	t.Run("toggles help on", func(t *testing.T) {
 		updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
 		if !updatedModel.(Model).showHelp {
@@ -407,7 +415,7 @@ func TestModel_LineSelectionAndScrolling(t *testing.T) {
 }
 
 func TestModel_Update_FileWatcher(t *testing.T) {
-	m := initialModel()
+	m := initialModel("")
 	_, cmd := m.Update(fileWatcherMsg{})
 
 	if cmd == nil {
@@ -421,9 +429,201 @@ func TestModel_Update_FileWatcher(t *testing.T) {
 	}
 }
 
+func TestModel_StagingViewNavigation(t *testing.T) {
+	tm := newTestModel()
+	tm.mainView = mainViewStaging
+	tm.focusedPanel = MainPanel
+	tm.activeSourcePanel = FilesPanel
+	tm.staging = stagingState{path: "file.txt"}
+
+	diff := git.ParseDiff("diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n" +
+		"@@ -1 +1 @@\n-a\n+b\n@@ -10 +10 @@\n-c\n+d\n")
+	updatedModel, _ := tm.Update(stagingDiffMsg{path: "file.txt", diff: &diff[0]})
+	tm.Model = updatedModel.(Model)
+
+	if !strings.Contains(tm.panels[MainPanel].content, "+b") {
+		t.Fatalf("staging view should render the diff, got: %s", tm.panels[MainPanel].content)
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Model = updatedModel.(Model)
+	if tm.staging.hunk != 1 {
+		t.Errorf("expected second hunk to be selected, got %d", tm.staging.hunk)
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Model = updatedModel.(Model)
+	if tm.mainView != mainViewDiff {
+		t.Error("escape should leave the staging view")
+	}
+	assertPanel(t, tm.focusedPanel, FilesPanel)
+}
+
+func TestModel_StagingLineMode(t *testing.T) {
+	tm := newTestModel()
+	tm.mainView = mainViewStaging
+	tm.focusedPanel = MainPanel
+	tm.activeSourcePanel = FilesPanel
+	tm.staging = stagingState{path: "file.txt"}
+
+	diff := git.ParseDiff("diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n" +
+		"@@ -1,2 +1,2 @@\n ctx\n-a\n+b\n")
+	updatedModel, _ := tm.Update(stagingDiffMsg{path: "file.txt", diff: &diff[0]})
+	tm.Model = updatedModel.(Model)
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("v")}, // enter line mode on "-a"
+		{Type: tea.KeyDown},                      // move to "+b"
+		{Type: tea.KeySpace},                     // select it
+	}
+	for _, k := range keys {
+		updatedModel, _ = tm.Update(k)
+		tm.Model = updatedModel.(Model)
+	}
+
+	if !tm.staging.lineMode {
+		t.Fatal("expected line mode to be active")
+	}
+	if tm.staging.line != 2 || !tm.staging.selected[2] {
+		t.Errorf("expected line 2 to be selected, got cursor %d and selection %v", tm.staging.line, tm.staging.selected)
+	}
+
+	// Escape leaves line mode first, then the staging view.
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Model = updatedModel.(Model)
+	if tm.staging.lineMode || tm.mainView != mainViewStaging {
+		t.Error("escape should only leave line mode")
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	files := []git.FileStatus{
+		{Path: "src/new -> name.go", OrigPath: "src/old.go", Index: git.StateRenamed, Worktree: git.StateUnmodified},
+		{Path: "src/main.go", Index: git.StateUnmodified, Worktree: git.StateModified},
+		{Path: "README.md", Index: git.StateUntracked, Worktree: git.StateUntracked},
+	}
+
+	root := BuildTree(files)
+	nodes := root.Nodes()
+	lines := root.Render(newTestModel().theme)
+	if len(nodes) != len(lines) {
+		t.Fatalf("nodes and lines should be parallel, got %d nodes and %d lines", len(nodes), len(lines))
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.path)
+	}
+	want := []string{"src", "src/main.go", "src/new -> name.go", "README.md"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+
+	if nodes[0].file != nil || !nodes[0].hasUnstagedChanges() {
+		t.Error("directory node should have no status and report unstaged changes below it")
+	}
+	if got := nodes[2].status(); got != "R " {
+		t.Errorf("expected rename status %q, got %q", "R ", got)
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	commit := func(hash string, parents ...string) git.CommitLog {
+		return git.CommitLog{Hash: hash, Parents: parents, SHA: hash, AuthorInitials: "AB", Subject: hash}
+	}
+	// c, e and f fork from b; m merges f into c, and d merges f into e.
+	commits := []git.CommitLog{
+		commit("d", "e", "f"),
+		commit("m", "c", "f"),
+		commit("f", "b"),
+		commit("e", "b"),
+		commit("c", "b"),
+		commit("b", "a"),
+		commit("a"),
+	}
+	want := []string{
+		"◎─╮",
+		"│ ├─◎",
+		"│ ● │",
+		"● │ │",
+		"│ │ ●",
+		"●─┴─╯",
+		"●",
+	}
+
+	rows := buildGraph(commits)
+	var got []string
+	for _, row := range rows {
+		got = append(got, row.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected graph:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A page of the log starts with the same rows as the whole log.
+	if page := buildGraph(commits[:3]); !reflect.DeepEqual(page, rows[:3]) {
+		t.Errorf("expected the first page to match the whole graph, got %v", page)
+	}
+
+	tm := newTestModel()
+	updatedModel, _ := tm.Update(commitLogUpdatedMsg{logs: commits, limit: tm.commitLog.limit})
+	tm.Model = updatedModel.(Model)
+	if line := tm.panels[CommitsPanel].lines[0]; line != "◎─╮\td\tAB\td" {
+		t.Errorf("unexpected commit line %q", line)
+	}
+	if commit, graph := tm.commitLogLine(0); commit == nil || commit.Hash != "d" || graph.String() != "◎─╮" {
+		t.Errorf("expected the first commit and its graph, got %+v, %q", commit, graph)
+	}
+	tm.panels[CommitsPanel].tab = reflogTab
+	if commit, _ := tm.commitLogLine(0); commit != nil {
+		t.Error("expected no commit log in the Reflog tab")
+	}
+}
+
+func TestModel_CommitLogColumns(t *testing.T) {
+	tm := newTestModel()
+	commit := &git.CommitLog{
+		Hash: "abc1234def", Parents: []string{"000"}, SHA: "abc1234",
+		AuthorName: "Jane Doe", AuthorInitials: "JD", AuthorDate: time.Now().Add(-3 * 24 * time.Hour),
+		Refs: []git.Ref{
+			{Name: "main", Kind: git.RefBranch, Head: true},
+			{Name: "origin/main", Kind: git.RefRemoteBranch},
+			{Name: "v1.0", Kind: git.RefTag},
+		},
+		Subject: "Fix bug",
+	}
+	graph := graphRow{{glyph: graphCommit, color: 0}}
+	render := func(width int) string {
+		return stripAnsi(tm.renderCommitLogLine(commit, graph, commit.Subject, width, lipgloss.NewStyle()))
+	}
+
+	labels := "(HEAD → main, origin/main, tag: v1.0) Fix bug"
+	if got, want := render(40), "● abc1234 JD "+labels; got != want {
+		t.Errorf("narrow panel: got %q, want %q", got, want)
+	}
+	if got, want := render(commitDateMinWidth), "● abc1234  3d JD "+labels; got != want {
+		t.Errorf("panel with dates: got %q, want %q", got, want)
+	}
+	if got, want := render(commitAuthorMinWidth), "● abc1234  3d Jane Doe       "+labels; got != want {
+		t.Errorf("wide panel: got %q, want %q", got, want)
+	}
+
+	for d, want := range map[time.Duration]string{
+		-time.Minute:            "0s",
+		90 * time.Minute:        "1h",
+		15 * 24 * time.Hour:     "2w",
+		400 * 24 * time.Hour:    "1y",
+		2 * 30 * 24 * time.Hour: "2M",
+	} {
+		if got := formatAge(d); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
 // newTestModel creates a new model with default dimensions and a calculated layout.
 func newTestModel() testModel {
-	m := initialModel()
+	m := initialModel("")
 	m.width = 100
 	m.height = 31
 	m = m.recalculateLayout()
@@ -445,3 +645,999 @@ func assertKeyBindingsEqual(t *testing.T, got, want []key.Binding) {
 		t.Errorf("\n\tgot \t%v\n\twant \t%v", got, want)
 	}
 }
+
+func TestModel_RebaseTodo(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = CommitsPanel
+	tm.activeSourcePanel = CommitsPanel
+
+	items := []git.RebaseTodoItem{
+		{Action: git.RebasePick, SHA: "aaa", Subject: "first"},
+		{Action: git.RebasePick, SHA: "bbb", Subject: "second"},
+		{Action: git.RebasePick, SHA: "ccc", Subject: "third"},
+	}
+	updatedModel, _ := tm.Update(rebaseTodoLoadedMsg{base: "aaa", items: items})
+	tm.Model = updatedModel.(Model)
+
+	if !tm.rebaseTodo.active {
+		t.Fatal("expected the rebase editor to be active")
+	}
+	// The newest commit is shown first, as in the commit log.
+	if tm.rebaseTodo.items[0].SHA != "ccc" || len(tm.panels[CommitsPanel].lines) != 3 {
+		t.Fatalf("unexpected todo items: %+v", tm.rebaseTodo.items)
+	}
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("f")}, // fixup "third"
+		{Type: tea.KeyCtrlJ},                     // move it below "second"
+		{Type: tea.KeyDown},                      // select "first"
+		{Type: tea.KeyRunes, Runes: []rune("d")}, // drop it
+	}
+	for _, k := range keys {
+		updatedModel, _ = tm.Update(k)
+		tm.Model = updatedModel.(Model)
+	}
+
+	got := tm.rebaseTodo.items
+	if got[0].SHA != "bbb" || got[1].SHA != "ccc" || got[1].Action != git.RebaseFixup || got[2].Action != git.RebaseDrop {
+		t.Errorf("unexpected todo items after editing: %+v", got)
+	}
+
+	// Commit log refreshes must not replace the editor.
+	updatedModel, _ = tm.Update(panelContentUpdatedMsg{panel: CommitsPanel, content: "log"})
+	tm.Model = updatedModel.(Model)
+	if len(tm.panels[CommitsPanel].lines) != 3 {
+		t.Errorf("expected the rebase editor to be kept, got lines %v", tm.panels[CommitsPanel].lines)
+	}
+
+	updatedModel, _ = tm.Update(rebaseRewordMsg{sha: "bbb", message: "new message"})
+	tm.Model = updatedModel.(Model)
+	if got := tm.rebaseTodo.items[0]; got.Action != git.RebaseReword || got.Message != "new message" {
+		t.Errorf("expected reword with new message, got %+v", got)
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Model = updatedModel.(Model)
+	if tm.rebaseTodo.active {
+		t.Error("escape should close the rebase editor")
+	}
+}
+
+func TestModel_ConflictView(t *testing.T) {
+	tm := newTestModel()
+	tm.mainView = mainViewConflicts
+	tm.focusedPanel = MainPanel
+	tm.activeSourcePanel = FilesPanel
+	tm.conflicts = conflictState{path: "file.txt"}
+
+	file := git.ParseConflicts("file.txt",
+		"<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nmiddle\n<<<<<<< HEAD\na\n=======\nb\n>>>>>>> feature\n")
+	updatedModel, _ := tm.Update(conflictFileMsg{path: "file.txt", file: file})
+	tm.Model = updatedModel.(Model)
+
+	if !strings.Contains(tm.panels[MainPanel].content, "theirs") {
+		t.Fatalf("conflict view should render the file, got: %s", tm.panels[MainPanel].content)
+	}
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("b")}, // both for the first block
+		{Type: tea.KeyDown},                      // select the second block
+		{Type: tea.KeyRunes, Runes: []rune("t")}, // theirs
+		{Type: tea.KeyRunes, Runes: []rune("o")}, // ours instead
+	}
+	for _, k := range keys {
+		updatedModel, _ = tm.Update(k)
+		tm.Model = updatedModel.(Model)
+	}
+
+	blocks := tm.conflicts.file.Blocks()
+	if blocks[0].Resolution != git.ResolutionBoth || blocks[1].Resolution != git.ResolutionOurs {
+		t.Errorf("unexpected resolutions: %v, %v", blocks[0].Resolution, blocks[1].Resolution)
+	}
+
+	// Pressing the same choice again clears it.
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	tm.Model = updatedModel.(Model)
+	if blocks[1].Resolution != git.ResolutionNone || tm.conflicts.file.IsResolved() {
+		t.Errorf("expected second block to be unresolved, got %v", blocks[1].Resolution)
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Model = updatedModel.(Model)
+	if tm.mainView != mainViewDiff {
+		t.Error("escape should leave the conflict view")
+	}
+	assertPanel(t, tm.focusedPanel, FilesPanel)
+}
+
+func TestModel_OperationBanner(t *testing.T) {
+	tm := newTestModel()
+	state := git.RepoState{Operation: git.OperationMerge, Branch: "master", Target: "abc1234"}
+
+	content := tm.statusContent("repo", &state)
+	if !strings.Contains(content, "MERGING abc1234") || !strings.Contains(content, "abort") {
+		t.Errorf("expected merge banner, got %q", content)
+	}
+	if strings.Contains(content, "skip") {
+		t.Errorf("a merge cannot be skipped, got %q", content)
+	}
+
+	updatedModel, _ := tm.Update(statusUpdatedMsg{content: content, state: state})
+	tm.Model = updatedModel.(Model)
+	if tm.panelHeights[StatusPanel] != statusPanelHeight+statusBannerHeight {
+		t.Errorf("expected Status panel to grow for the banner, got height %d", tm.panelHeights[StatusPanel])
+	}
+
+	updatedModel, _ = tm.Update(statusUpdatedMsg{content: "repo → master"})
+	tm.Model = updatedModel.(Model)
+	if tm.panelHeights[StatusPanel] != statusPanelHeight {
+		t.Errorf("expected Status panel to shrink without the banner, got height %d", tm.panelHeights[StatusPanel])
+	}
+}
+
+func TestModel_CopyCommits(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = CommitsPanel
+	tm.activeSourcePanel = CommitsPanel
+	tm.panels[CommitsPanel].lines = []string{
+		"○\tccc\tAB\tthird",
+		"│",
+		"○\tbbb\tAB\tsecond",
+		"○\taaa\tAB\tfirst",
+	}
+
+	copyKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")}
+	for _, cursor := range []int{0, 3} {
+		tm.panels[CommitsPanel].cursor = cursor
+		updatedModel, _ := tm.Update(copyKey)
+		tm.Model = updatedModel.(Model)
+	}
+
+	// Commits are applied from oldest to newest, regardless of marking order.
+	if !reflect.DeepEqual(tm.copiedCommits, []string{"aaa", "ccc"}) {
+		t.Errorf("got copied commits %v, want [aaa ccc]", tm.copiedCommits)
+	}
+	if line := tm.decorateCommitLine(tm.panels[CommitsPanel].lines[0]); !strings.Contains(line, copiedCommitMarker) {
+		t.Errorf("expected copied commit to be marked, got %q", line)
+	}
+
+	// Copying a marked commit again unmarks it.
+	updatedModel, _ := tm.Update(copyKey)
+	tm.Model = updatedModel.(Model)
+	if !reflect.DeepEqual(tm.copiedCommits, []string{"ccc"}) {
+		t.Errorf("got copied commits %v, want [ccc]", tm.copiedCommits)
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Model = updatedModel.(Model)
+	if len(tm.copiedCommits) != 0 {
+		t.Errorf("escape should clear copied commits, got %v", tm.copiedCommits)
+	}
+}
+
+func TestModel_BisectLabels(t *testing.T) {
+	tm := newTestModel()
+	tm.repoState = git.RepoState{
+		Operation: git.OperationBisect,
+		Bisect: &git.BisectState{
+			Bad:       "ccc1111",
+			Good:      []string{"aaa1111"},
+			Current:   "bbb1111",
+			Remaining: 1,
+			Steps:     1,
+		},
+	}
+
+	tests := map[string]string{
+		"○\tccc\tAB\tthird":  "[bad]",
+		"○\tbbb\tAB\tsecond": "[current]",
+		"○\taaa\tAB\tfirst":  "[good]",
+	}
+	for line, label := range tests {
+		if got := tm.decorateCommitLine(line); !strings.Contains(got, label) {
+			t.Errorf("decorateCommitLine(%q) = %q, want label %s", line, got, label)
+		}
+	}
+
+	if banner := bisectBanner(&tm.repoState); !strings.Contains(banner, "1 revisions left") {
+		t.Errorf("unexpected bisect banner %q", banner)
+	}
+	tm.repoState.Bisect.FirstBad = "ccc1111"
+	if banner := bisectBanner(&tm.repoState); !strings.Contains(banner, "first bad commit is ccc1111") {
+		t.Errorf("unexpected bisect banner %q", banner)
+	}
+}
+
+func TestModel_BranchTracking(t *testing.T) {
+	tm := newTestModel()
+	state := git.RepoState{Branch: "master", Tracking: git.Tracking{Upstream: "origin/master", Ahead: 2, Behind: 1}}
+	if content := stripAnsi(tm.statusContent("repo", &state)); !strings.Contains(content, "master ↑2 ↓1") {
+		t.Errorf("expected tracking in the status, got %q", content)
+	}
+
+	tests := map[git.Tracking]string{
+		{}:                                 "",
+		{Upstream: "origin/a"}:             "✓",
+		{Upstream: "origin/a", Ahead: 3}:   "↑3",
+		{Upstream: "origin/a", Behind: 4}:  "↓4",
+		{Upstream: "origin/a", Gone: true}: "upstream gone",
+	}
+	for tracking, want := range tests {
+		if got := formatTracking(tracking); got != want {
+			t.Errorf("formatTracking(%+v) = %q, want %q", tracking, got, want)
+		}
+	}
+
+	line := styleUnselectedLine("2d\t(*) → master\t↑2", BranchesPanel, tm.theme)
+	if got := stripAnsi(line); got != "2d (*) → master ↑2" {
+		t.Errorf("got branch line %q", got)
+	}
+}
+
+func TestModel_BlameView(t *testing.T) {
+	tm := newTestModel()
+	tm.panels[CommitsPanel].lines = []string{"○\tbbbbbbb\tAB\tsecond", "○\taaaaaaa\tAB\tfirst"}
+	tm.Model.enterBlameView("file.txt")
+
+	first := &git.BlameCommit{SHA: "aaaaaaa1111", Author: "Alice", Summary: "first"}
+	second := &git.BlameCommit{SHA: "bbbbbbb2222", Author: "Bob", Summary: "second", Previous: "aaaaaaa1111", PreviousPath: "old.txt"}
+	lines := []git.BlameLine{
+		{Commit: first, Line: 1, Content: "one"},
+		{Commit: second, Line: 2, Content: "two"},
+	}
+	updatedModel, _ := tm.Update(blameLoadedMsg{path: "file.txt", lines: lines})
+	tm.Model = updatedModel.(Model)
+	if !strings.Contains(tm.panels[MainPanel].content, "Alice") || !strings.Contains(tm.blameTitle(), "line 1/2") {
+		t.Fatalf("expected the blame to be rendered, got title %q", tm.blameTitle())
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	tm.Model = updatedModel.(Model)
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	tm.Model = updatedModel.(Model)
+	if tm.blame.path != "old.txt" || tm.blame.revision != "aaaaaaa1111" || cmd == nil {
+		t.Fatalf("expected the parent to be blamed, got %+v", tm.blame)
+	}
+
+	updatedModel, _ = tm.Update(blameLoadedMsg{path: "old.txt", revision: "aaaaaaa1111", lines: lines[:1]})
+	tm.Model = updatedModel.(Model)
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Model = updatedModel.(Model)
+	assertPanel(t, tm.focusedPanel, CommitsPanel)
+	if tm.mainView != mainViewDiff || tm.panels[CommitsPanel].cursor != 1 {
+		t.Errorf("expected the commit to be selected, got cursor %d", tm.panels[CommitsPanel].cursor)
+	}
+
+	// A commit older than the loaded ones is selected once its page is loaded.
+	tm.Model.enterBlameView("file.txt")
+	tm.commitLog = commitLogState{limit: 2, more: true, logs: []git.CommitLog{{Hash: "bbbbbbb"}, {Hash: "aaaaaaa"}}}
+	tm.commitLog.graph = buildGraph(tm.commitLog.logs)
+	old := &git.BlameCommit{SHA: "0000000ffff", Author: "Carol", Summary: "initial"}
+	updatedModel, _ = tm.Update(blameLoadedMsg{path: "file.txt", lines: []git.BlameLine{{Commit: old, Line: 1, Content: "one"}}})
+	tm.Model = updatedModel.(Model)
+	updatedModel, cmd = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Model = updatedModel.(Model)
+	if tm.mainView != mainViewBlame || !tm.commitLog.loading || tm.commitLog.jumpTo != old.SHA || cmd == nil {
+		t.Fatalf("expected the next page to be loaded for the jump, got %+v", tm.commitLog)
+	}
+	page := commitLogUpdatedMsg{skip: 2, limit: commitPageSize, logs: []git.CommitLog{{Hash: "0000000", SHA: "0000000", Subject: "initial"}}}
+	updatedModel, _ = tm.Update(page)
+	tm.Model = updatedModel.(Model)
+	if tm.mainView != mainViewDiff || tm.panels[CommitsPanel].cursor != 2 {
+		t.Errorf("expected the older commit to be selected, got view %v, cursor %d", tm.mainView, tm.panels[CommitsPanel].cursor)
+	}
+}
+
+func TestModel_FileHistory(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = CommitsPanel
+	tm.activeSourcePanel = CommitsPanel
+
+	updatedModel, _ := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeInput {
+		t.Fatalf("expected a prompt for the file, got mode %v", tm.mode)
+	}
+	msg := tm.inputCallback("new.txt")()
+	tm.mode = modeNormal
+	updatedModel, cmd := tm.Update(msg)
+	tm.Model = updatedModel.(Model)
+	if tm.fileHistory.path != "new.txt" || cmd == nil {
+		t.Fatalf("expected the history of new.txt to be fetched, got %+v", tm.fileHistory)
+	}
+
+	// The full commit log fetched before is discarded.
+	updatedModel, _ = tm.Update(panelContentUpdatedMsg{panel: CommitsPanel, content: "○\tccc\tAB\tlog"})
+	tm.Model = updatedModel.(Model)
+	if len(tm.panels[CommitsPanel].lines) != 0 {
+		t.Errorf("expected the commit log to be ignored, got %v", tm.panels[CommitsPanel].lines)
+	}
+
+	logs := []git.CommitLog{
+		{Graph: "○", SHA: "bbb", AuthorInitials: "AB", Subject: "Rename", Path: "new.txt"},
+		{Graph: "○", SHA: "aaa", AuthorInitials: "AB", Subject: "Add", Path: "old.txt"},
+	}
+	updatedModel, _ = tm.Update(fileHistoryUpdatedMsg{path: "new.txt", logs: logs})
+	tm.Model = updatedModel.(Model)
+	if len(tm.panels[CommitsPanel].lines) != 2 || tm.fileHistory.paths["aaa"] != "old.txt" {
+		t.Fatalf("unexpected history lines %v", tm.panels[CommitsPanel].lines)
+	}
+	if title := tm.commitsTitle(); !strings.Contains(title, "history of new.txt") {
+		t.Errorf("unexpected title %q", title)
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Model = updatedModel.(Model)
+	if tm.fileHistory.path != "" {
+		t.Errorf("escape should show the full log again, got %+v", tm.fileHistory)
+	}
+}
+
+func TestModel_CommitLogPaging(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = CommitsPanel
+	tm.activeSourcePanel = CommitsPanel
+
+	// ddd and bbb are on two branches, which fork from aaa.
+	commits := map[string][]string{"eee": {"ddd"}, "ddd": {"ccc"}, "bbb": {"aaa"}, "ccc": {"aaa"}, "aaa": nil}
+	page := func(skip, limit int, shas ...string) commitLogUpdatedMsg {
+		msg := commitLogUpdatedMsg{skip: skip, limit: limit}
+		for _, sha := range shas {
+			msg.logs = append(msg.logs, git.CommitLog{Hash: sha, Parents: commits[sha], SHA: sha, AuthorInitials: "AB", Subject: sha})
+		}
+		return msg
+	}
+	tm.commitLog.limit = 3
+	updatedModel, _ := tm.Update(page(0, 3, "ddd", "bbb", "ccc"))
+	tm.Model = updatedModel.(Model)
+	if !tm.commitLog.more {
+		t.Fatal("expected more commits after a full page")
+	}
+
+	// Moving near the end of the loaded commits loads the next page.
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	tm.Model = updatedModel.(Model)
+	if !tm.commitLog.loading || tm.commitLog.limit != 3 {
+		t.Fatalf("expected the next page to be loaded, got %+v", tm.commitLog)
+	}
+
+	// The page is appended, and the graph continues the lanes of the loaded commits.
+	updatedModel, _ = tm.Update(page(3, commitPageSize, "aaa"))
+	tm.Model = updatedModel.(Model)
+	if tm.commitLog.more || tm.commitLog.loading || tm.commitLog.limit != 3+commitPageSize {
+		t.Errorf("expected the whole log to be loaded, got %+v", tm.commitLog)
+	}
+	if got, want := tm.commitLog.graph, buildGraph(page(0, 0, "ddd", "bbb", "ccc", "aaa").logs); !reflect.DeepEqual(got, want) {
+		t.Errorf("got graph %v, want %v", got, want)
+	}
+	if len(tm.panels[CommitsPanel].lines) != 4 || tm.panels[CommitsPanel].cursor != 1 {
+		t.Errorf("expected the page to be appended below the cursor, got cursor %d in %q", tm.panels[CommitsPanel].cursor, tm.panels[CommitsPanel].lines)
+	}
+
+	// A page that does not follow the loaded commits is dropped.
+	updatedModel, _ = tm.Update(page(3, commitPageSize, "aaa"))
+	tm.Model = updatedModel.(Model)
+	if len(tm.commitLog.logs) != 4 {
+		t.Errorf("expected a stale page to be dropped, got %d commits", len(tm.commitLog.logs))
+	}
+
+	// A refresh with a new commit on top keeps the cursor on the selected commit.
+	updatedModel, _ = tm.Update(page(0, tm.commitLog.limit, "eee", "ddd", "bbb", "ccc", "aaa"))
+	tm.Model = updatedModel.(Model)
+	if got := commitLineSHA(tm.panels[CommitsPanel].lines[tm.panels[CommitsPanel].cursor]); got != "bbb" {
+		t.Errorf("expected the cursor to stay on bbb, got %s", got)
+	}
+	if tm.commitLog.more || tm.commitLog.loading {
+		t.Errorf("expected the whole log to be loaded, got %+v", tm.commitLog)
+	}
+}
+
+func TestModel_OutputView(t *testing.T) {
+	tm := newTestModel()
+	lines := make(chan outputLine, 8)
+	w := &lineWriter{lines: lines}
+	_, _ = w.Write([]byte("start\nprogress 50%\rprogress 100%\r\ndone"))
+	w.flush()
+	close(lines)
+
+	tm.Model.startOutputView("test", nil)
+	for line := range lines {
+		tm.Model, _ = tm.handleOutputLineMsg(outputLineMsg{line: line.text, replace: line.replace, lines: tm.output.stream})
+	}
+	want := []string{"start", "progress 100%", "done"}
+	if !reflect.DeepEqual(tm.output.lines, want) {
+		t.Errorf("got output lines %q, want %q", tm.output.lines, want)
+	}
+
+	tm.Model, _ = tm.handleOutputDoneMsg(outputDoneMsg{cmdStr: "git test", lines: tm.output.stream})
+	if tm.output.running || tm.outputTitle() != "test" {
+		t.Errorf("expected finished output view, got title %q", tm.outputTitle())
+	}
+}
+
+func TestModel_OutputWhilePopupShown(t *testing.T) {
+	tm := newTestModel()
+	release := make(chan struct{})
+	cmd := tm.startRemoteCommand("git fetch", func(w io.Writer) (string, error) {
+		_, _ = io.WriteString(w, "Receiving objects: 50%\r")
+		<-release
+		_, _ = io.WriteString(w, "Receiving objects: 100%\r\ndone\n")
+		return "git fetch", nil
+	})
+
+	// Run the commands the way the program does, feeding their messages back
+	// into Update, until the fetch has finished.
+	msgs := make(chan tea.Msg)
+	stop := make(chan struct{})
+	defer close(stop)
+	var run func(tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, cmd := range batch {
+					run(cmd)
+				}
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-stop:
+			}
+		}()
+	}
+	run(cmd)
+
+	// A confirmation pop-up is opened while the command is writing its output.
+	tm.mode = modeConfirm
+	tm.confirmCallback = func(bool) tea.Cmd { return nil }
+	close(release)
+
+	timeout := time.After(5 * time.Second)
+	for tm.progress.running {
+		select {
+		case msg := <-msgs:
+			updatedModel, cmd := tm.Update(msg)
+			tm.Model = updatedModel.(Model)
+			if _, ok := msg.(outputDoneMsg); !ok {
+				run(cmd)
+			}
+		case <-timeout:
+			t.Fatalf("the output stalled while a pop-up was shown, progress %+v", tm.progress)
+		}
+	}
+	if tm.mode != modeConfirm {
+		t.Errorf("expected the pop-up to stay open, got mode %v", tm.mode)
+	}
+}
+
+func TestModel_ActionHeldWhilePopupShown(t *testing.T) {
+	tm := newTestModel()
+	confirmed := false
+	tm.mode = modeConfirm
+	tm.confirmCallback = func(bool) tea.Cmd { confirmed = true; return nil }
+
+	// A prompt result arriving behind the pop-up does not replace it.
+	msg := publishBranchMsg{branch: "feature", remote: "origin"}
+	updatedModel, cmd := tm.Update(msg)
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeConfirm || cmd != nil || tm.progress.running || len(tm.heldMsgs) != 1 {
+		t.Fatalf("expected the message to be held, got mode %v, held %v", tm.mode, tm.heldMsgs)
+	}
+
+	// Once the pop-up is closed, the held message is sent again.
+	updatedModel, cmd = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	tm.Model = updatedModel.(Model)
+	if !confirmed || tm.mode != modeNormal || len(tm.heldMsgs) != 0 || cmd == nil {
+		t.Fatalf("expected the held message to be released, got mode %v, held %v", tm.mode, tm.heldMsgs)
+	}
+	updatedModel, _ = tm.Update(msg)
+	tm.Model = updatedModel.(Model)
+	if !tm.progress.running {
+		t.Error("expected the branch to be published once the pop-up is closed")
+	}
+}
+
+func TestLineWriter_Cancelled(t *testing.T) {
+	done := make(chan struct{})
+	w := &lineWriter{lines: make(chan outputLine), done: done}
+	close(done)
+	// Nobody reads the lines, which must not block the command.
+	_, _ = w.Write([]byte("one\ntwo"))
+	w.flush()
+}
+
+func TestModel_SyncProgress(t *testing.T) {
+	tm := newTestModel()
+	tm.CommandHistory = []string{"$ git status"}
+
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	tm.Model = updatedModel.(Model)
+	if !tm.progress.running || cmd == nil {
+		t.Fatalf("expected a fetch to be started, got progress %+v", tm.progress)
+	}
+	if cmd := tm.startRemoteCommand("git push", nil); cmd == nil {
+		t.Error("expected an error while another remote command is running")
+	}
+
+	tm.Model, _ = tm.handleOutputLineMsg(outputLineMsg{target: outputToLog, line: "Receiving objects: 50%", lines: tm.progress.stream})
+	log := tm.panels[SecondaryPanel].content
+	if !strings.Contains(log, "Receiving objects: 50%") || !strings.Contains(log, "$ git status") {
+		t.Errorf("expected progress above the command history, got %q", log)
+	}
+
+	tm.Model, _ = tm.handleOutputDoneMsg(outputDoneMsg{target: outputToLog, cmdStr: "git fetch --all", lines: tm.progress.stream})
+	if tm.progress.running || strings.Contains(tm.panels[SecondaryPanel].content, "Receiving") {
+		t.Errorf("expected progress to be cleared, got %q", tm.panels[SecondaryPanel].content)
+	}
+
+	// Pushing a branch without an upstream publishes it.
+	tm.repoState = git.RepoState{Branch: "feature"}
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeInput || tm.textInput.Value() != defaultRemote {
+		t.Fatalf("expected a prompt for the remote to publish to, got mode %v", tm.mode)
+	}
+	msg := tm.inputCallback("origin")()
+	if msg != (publishBranchMsg{branch: "feature", remote: "origin"}) {
+		t.Fatalf("got %#v, want publishBranchMsg", msg)
+	}
+	tm.mode = modeNormal
+	updatedModel, _ = tm.Update(msg)
+	tm.Model = updatedModel.(Model)
+	if !tm.progress.running || tm.progress.title != "git push --set-upstream origin feature" {
+		t.Errorf("expected the branch to be published, got progress %+v", tm.progress)
+	}
+
+	// The stash panel uses "p" to pop a stash rather than to pull.
+	tm.focusedPanel = StashPanel
+	if !tm.panelBindsSyncKeys() {
+		t.Error("expected the stash panel to bind the sync keys")
+	}
+}
+
+func TestModel_ReflogTab(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = CommitsPanel
+	tm.activeSourcePanel = CommitsPanel
+
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	tm.Model = updatedModel.(Model)
+	if tm.panels[CommitsPanel].tab != reflogTab || cmd == nil {
+		t.Fatalf("expected the Reflog tab to be shown and fetched, got tab %v", tm.panels[CommitsPanel].tab)
+	}
+
+	// Content fetched for the commit log before switching is discarded.
+	updatedModel, _ = tm.Update(panelContentUpdatedMsg{panel: CommitsPanel, tab: defaultTab, content: "○\taaa\tAB\tlog"})
+	tm.Model = updatedModel.(Model)
+	if len(tm.panels[CommitsPanel].lines) != 0 {
+		t.Errorf("expected stale commit log to be ignored, got %v", tm.panels[CommitsPanel].lines)
+	}
+
+	content := "HEAD@{0}\tbbb\treset: moving to aaa\nHEAD@{1}\tccc\tcommit: second"
+	updatedModel, _ = tm.Update(panelContentUpdatedMsg{panel: CommitsPanel, tab: reflogTab, content: content})
+	tm.Model = updatedModel.(Model)
+	if got := commitLineSHA(tm.panels[CommitsPanel].lines[1]); got != "ccc" {
+		t.Errorf("got reflog commit %q, want ccc", got)
+	}
+	if !reflect.DeepEqual(tm.panelShortHelp(), tm.keymap.ReflogHelp()) {
+		t.Error("expected the reflog help in the help bar")
+	}
+
+	// Undoing an action that resets a dirty working tree asks for confirmation.
+	move := git.HeadMove{Kind: git.HeadMoveReset, Target: "ccc", Action: "reset: moving to aaa"}
+	tm.Model, cmd = tm.handleUndoPlannedMsg(undoPlannedMsg{move: move, dirty: true})
+	if tm.mode != modeConfirm || cmd != nil {
+		t.Errorf("expected a confirmation before discarding changes, got mode %v", tm.mode)
+	}
+	tm.mode = modeNormal
+	move.Kind = git.HeadMoveCheckout
+	tm.Model, cmd = tm.handleUndoPlannedMsg(undoPlannedMsg{move: move, dirty: true})
+	if tm.mode != modeNormal || cmd == nil {
+		t.Error("expected a checkout to be undone without confirmation")
+	}
+
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	tm.Model = updatedModel.(Model)
+	if tm.panels[CommitsPanel].tab != defaultTab {
+		t.Errorf("expected the commit log tab to be shown again, got tab %v", tm.panels[CommitsPanel].tab)
+	}
+}
+
+func TestModel_RemotesTab(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = BranchesPanel
+	tm.activeSourcePanel = BranchesPanel
+	updatedModel, _ := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	tm.Model = updatedModel.(Model)
+	if tm.panels[BranchesPanel].tab != remotesTab {
+		t.Fatalf("expected the Remotes tab, got tab %v", tm.panels[BranchesPanel].tab)
+	}
+
+	origin := &git.Remote{Name: "origin", FetchURL: "url", PushURL: "url"}
+	upstream := &git.Remote{Name: "upstream", FetchURL: "other", PushURL: "other"}
+	updatedModel, _ = tm.Update(remotesUpdatedMsg{remotes: []*git.Remote{origin, upstream}})
+	tm.Model = updatedModel.(Model)
+	if len(tm.panels[BranchesPanel].lines) != 2 {
+		t.Fatalf("expected a line per remote, got %v", tm.panels[BranchesPanel].lines)
+	}
+
+	// Expanding a remote lists its branches and keeps the cursor on it.
+	tm.panels[BranchesPanel].cursor = 1
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Model = updatedModel.(Model)
+	if !tm.isRemoteExpanded("upstream") || cmd == nil {
+		t.Fatalf("expected upstream to be expanded, got %v", tm.remotes.expanded)
+	}
+	upstream.Branches = []*git.RemoteBranch{{Remote: "upstream", Name: "main"}, {Remote: "upstream", Name: "dev"}}
+	updatedModel, _ = tm.Update(remotesUpdatedMsg{remotes: []*git.Remote{origin, upstream}})
+	tm.Model = updatedModel.(Model)
+	if len(tm.remotes.items) != 4 || tm.panels[BranchesPanel].cursor != 1 {
+		t.Fatalf("unexpected items %+v with cursor %d", tm.remotes.items, tm.panels[BranchesPanel].cursor)
+	}
+
+	// Checking out a remote branch asks for the local branch name.
+	tm.panels[BranchesPanel].cursor = 3
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeInput || tm.textInput.Value() != "dev" {
+		t.Errorf("expected a prompt for the local branch name, got mode %v and value %q", tm.mode, tm.textInput.Value())
+	}
+}
+
+func TestModel_TagsTab(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = CommitsPanel
+	tm.activeSourcePanel = CommitsPanel
+	tm.panels[CommitsPanel].lines = []string{"○\taaa\tAB\tfirst"}
+
+	// Tagging a commit asks for the name, then for the message.
+	updatedModel, _ := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeInput {
+		t.Fatalf("expected a prompt for the tag name, got mode %v", tm.mode)
+	}
+	msg := tm.inputCallback("v1.0")()
+	if got, ok := msg.(tagMessageMsg); !ok || got.name != "v1.0" || got.commit != "aaa" {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+
+	tm.mode = modeNormal
+	tm.focusedPanel = BranchesPanel
+	tm.panels[BranchesPanel].tab = tagsTab
+	tags := []*git.Tag{
+		{Name: "v1.0", Commit: "aaa", Annotated: true, Message: "Release"},
+		{Name: "light", Commit: "bbb"},
+	}
+	updatedModel, _ = tm.Update(tagsUpdatedMsg{tags: tags})
+	tm.Model = updatedModel.(Model)
+	if len(tm.panels[BranchesPanel].lines) != 2 || !strings.Contains(stripAnsi(tm.panels[BranchesPanel].lines[0]), "v1.0 aaa annotated Release") {
+		t.Fatalf("unexpected tag lines %q", tm.panels[BranchesPanel].lines)
+	}
+
+	tm.panels[BranchesPanel].cursor = 1
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeInput || tm.textInput.Value() != defaultRemote {
+		t.Errorf("expected a prompt for the remote to push light to, got mode %v", tm.mode)
+	}
+
+	// The tag is pushed in the background like the other remote commands.
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Model = updatedModel.(Model)
+	updatedModel, _ = tm.Update(cmd())
+	tm.Model = updatedModel.(Model)
+	if !tm.progress.running || tm.progress.title != "git push origin light" || tm.runningCommand() == "" {
+		t.Errorf("expected the tag push to show its progress, got %+v", tm.progress)
+	}
+}
+
+func TestModel_CancelGitCommands(t *testing.T) {
+	tm := newTestModel()
+	tm.activeSourcePanel = CommitsPanel
+	tm.panels[CommitsPanel].lines = []string{"○\tHEAD\tAB\tfirst", "○\tHEAD~1\tAB\tsecond"}
+
+	// Moving the cursor cancels the fetch for the previous commit.
+	stale := tm.updateMainPanel()
+	tm.panels[CommitsPanel].cursor = 1
+	latest := tm.updateMainPanel()
+	if msg := stale(); msg != nil {
+		t.Errorf("expected the stale fetch to be dropped, got %#v", msg)
+	}
+	if _, ok := latest().(mainContentUpdatedMsg); !ok {
+		t.Error("expected the latest fetch to update the Main panel")
+	}
+
+	ctx := tm.git.Context()
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	tm.Model = updatedModel.(Model)
+	if ctx.Err() == nil {
+		t.Error("expected the running git commands to be cancelled")
+	}
+	if tm.git.Context().Err() != nil {
+		t.Error("expected later git commands to run in a new context")
+	}
+	if cmd == nil {
+		t.Error("expected the panels to be refreshed")
+	}
+}
+
+func TestTimeoutsConfig(t *testing.T) {
+	timeouts := timeoutsConfig{Command: "10s", LongRunning: "invalid"}.gitTimeouts()
+	if timeouts.Command != 10*time.Second || timeouts.LongRunning != defaultLongRunningTimeout {
+		t.Errorf("unexpected timeouts: %+v", timeouts)
+	}
+	if timeouts := (timeoutsConfig{}).gitTimeouts(); timeouts.Command != defaultCommandTimeout {
+		t.Errorf("expected the default command timeout, got %+v", timeouts)
+	}
+}
+
+func TestModel_ErrorHint(t *testing.T) {
+	tm := newTestModel()
+	err := fmt.Errorf("failed to add files: %w", &git.GitError{Kind: git.ErrorLockHeld, ExitCode: 128, Stderr: "fatal: Unable to create index.lock"})
+	updatedModel, _ := tm.Update(errMsg{err})
+	tm.Model = updatedModel.(Model)
+	if len(tm.CommandHistory) == 0 || !strings.Contains(tm.CommandHistory[0], "remove the index.lock file") {
+		t.Errorf("expected a hint for the held lock, got %q", tm.CommandHistory)
+	}
+	if hint := errorHint(errors.New("other")); hint != "" {
+		t.Errorf("expected no hint for other errors, got %q", hint)
+	}
+}
+
+func TestWorkspaceRepos(t *testing.T) {
+	scanDir := t.TempDir()
+	for _, dir := range []string{"repo-a/.git", "repo-b/.git", "not-a-repo"} {
+		if err := os.MkdirAll(filepath.Join(scanDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	repoA, repoB := filepath.Join(scanDir, "repo-a"), filepath.Join(scanDir, "repo-b")
+
+	workspace := workspaceConfig{Repos: []string{"/configured", repoA}, ScanDirs: []string{scanDir}}
+	got := workspaceRepos(workspace, []string{repoB})
+	want := []string{repoB, "/configured", repoA}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got repos %v, want %v", got, want)
+	}
+}
+
+func TestRecentRepos(t *testing.T) {
+	original := RecentReposFilePath
+	RecentReposFilePath = filepath.Join(t.TempDir(), RecentReposFileName)
+	defer func() { RecentReposFilePath = original }()
+
+	for _, path := range []string{"/a", "/b", "/a"} {
+		if err := addRecentRepo(path); err != nil {
+			t.Fatalf("addRecentRepo(%s) failed: %v", path, err)
+		}
+	}
+	if got := loadRecentRepos(); !reflect.DeepEqual(got, []string{"/a", "/b"}) {
+		t.Errorf("got recent repos %v, want [/a /b]", got)
+	}
+
+	for i := range maxRecentRepos + 5 {
+		if err := addRecentRepo(fmt.Sprintf("/repo-%d", i)); err != nil {
+			t.Fatalf("addRecentRepo() failed: %v", err)
+		}
+	}
+	if got := loadRecentRepos(); len(got) != maxRecentRepos {
+		t.Errorf("expected %d recent repos, got %d", maxRecentRepos, len(got))
+	}
+}
+
+func TestModel_RepoSwitcher(t *testing.T) {
+	original := RecentReposFilePath
+	RecentReposFilePath = filepath.Join(t.TempDir(), RecentReposFileName)
+	defer func() { RecentReposFilePath = original }()
+
+	repo := t.TempDir()
+	if err := exec.Command("git", "init", repo).Run(); err != nil {
+		t.Fatalf("failed to init repository: %v", err)
+	}
+	repo, _ = filepath.EvalSymlinks(repo)
+
+	tm := newTestModel()
+	tm.workspace = workspaceConfig{Repos: []string{repo}}
+	updatedModel, _ := tm.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeRepoSwitcher {
+		t.Fatalf("expected the repository switcher, got mode %v", tm.mode)
+	}
+	if !slices.Contains(tm.repoSwitcher.repos, repo) {
+		t.Fatalf("expected %s to be listed, got %v", repo, tm.repoSwitcher.repos)
+	}
+
+	// A path that is not a repository keeps the switcher open with the error.
+	msg := openRepo(t.TempDir())()
+	updatedModel, _ = tm.Update(msg)
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeRepoSwitcher || tm.repoSwitcher.err == nil {
+		t.Errorf("expected the switcher to show the error, got %+v", tm.repoSwitcher)
+	}
+
+	tm.CommandHistory = []string{"git status"}
+	updatedModel, cmd := tm.Update(openRepo(repo)())
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeNormal || tm.repoRoot != repo || tm.git.RepoPath() != repo {
+		t.Errorf("expected %s to be opened, got root %q, mode %v", repo, tm.repoRoot, tm.mode)
+	}
+	if len(tm.CommandHistory) != 1 || cmd == nil {
+		t.Errorf("expected the command log to be kept and the panels to be loaded")
+	}
+
+	// Switching while a remote command is running asks before cancelling it,
+	// and the messages of the cancelled command are dropped.
+	release := make(chan struct{})
+	defer close(release)
+	tm.startRemoteCommand("git push", func(w io.Writer) (string, error) {
+		<-release
+		return "git push", nil
+	})
+	stale := tm.progress.stream
+	updatedModel, _ = tm.Update(openRepo(repo)())
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeConfirm || !strings.Contains(tm.confirmMessage, "git push is still running") {
+		t.Fatalf("expected a confirmation to cancel the push, got mode %v", tm.mode)
+	}
+	updatedModel, cmd = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	tm.Model = updatedModel.(Model)
+	updatedModel, _ = tm.Update(cmd())
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeNormal || tm.progress.running {
+		t.Fatalf("expected %s to be opened again, got mode %v, progress %+v", repo, tm.mode, tm.progress)
+	}
+	tm.startRemoteCommand("git fetch", func(io.Writer) (string, error) { return "git fetch", nil })
+	updatedModel, _ = tm.Update(outputDoneMsg{target: outputToLog, cmdStr: "git push", lines: stale})
+	tm.Model = updatedModel.(Model)
+	if !tm.progress.running || tm.progress.title != "git fetch" {
+		t.Errorf("expected the stale push to leave the fetch running, got %+v", tm.progress)
+	}
+
+	// Without a repository, keys open the switcher, and escape quits.
+	tm.repoRoot = ""
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeRepoSwitcher {
+		t.Errorf("expected the repository switcher without a repository, got mode %v", tm.mode)
+	}
+	if _, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd == nil {
+		t.Error("expected escape to quit without a repository")
+	}
+}
+
+func TestModel_Worktrees(t *testing.T) {
+	tm := newTestModel()
+	tm.focusedPanel = FilesPanel
+	tm.repoRoot = "/repo"
+	tm.fileNodes = []*Node{{path: "file.txt"}}
+
+	updatedModel, _ := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	tm.Model = updatedModel.(Model)
+	if tm.panels[FilesPanel].tab != worktreesTab {
+		t.Fatalf("expected the Worktrees tab, got %v", tm.panels[FilesPanel].tab)
+	}
+	if tm.selectedFileNode() != nil {
+		t.Error("expected no file to be selected in the Worktrees tab")
+	}
+
+	worktrees := []*git.Worktree{
+		{Path: "/repo", Branch: "main", Main: true},
+		{Path: "/repo-hotfix", Branch: "hotfix", Locked: true},
+	}
+	updatedModel, _ = tm.Update(worktreesUpdatedMsg{worktrees: worktrees})
+	tm.Model = updatedModel.(Model)
+	lines := tm.panels[FilesPanel].lines
+	if len(lines) != 2 || !strings.Contains(stripAnsi(lines[0]), "(*) → repo main") || !strings.Contains(stripAnsi(lines[1]), "locked") {
+		t.Errorf("unexpected worktree lines: %q", lines)
+	}
+
+	// The main worktree cannot be removed.
+	updatedModel, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	tm.Model = updatedModel.(Model)
+	if msg, ok := cmd().(errMsg); !ok || tm.mode != modeNormal {
+		t.Errorf("expected an error for removing the main worktree, got %#v", msg)
+	}
+
+	tm.panels[FilesPanel].cursor = 1
+	updatedModel, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	tm.Model = updatedModel.(Model)
+	if tm.mode != modeConfirm || !strings.Contains(tm.confirmMessage, "/repo-hotfix") {
+		t.Errorf("expected a confirmation to remove the worktree, got %q", tm.confirmMessage)
+	}
+	tm.mode = modeNormal
+
+	// Another worktree is opened like a repository.
+	_, cmd = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(repoOpenedMsg); !ok || msg.err == nil {
+		t.Errorf("expected opening the missing worktree to fail, got %#v", msg)
+	}
+
+	if got := formatBranchWorktree(&git.Branch{Name: "hotfix", Worktree: "/repo-hotfix"}); got != "⎇ repo-hotfix" {
+		t.Errorf("unexpected branch worktree marker %q", got)
+	}
+}
+
+func TestModel_Submodules(t *testing.T) {
+	original := RecentReposFilePath
+	RecentReposFilePath = filepath.Join(t.TempDir(), RecentReposFileName)
+	defer func() { RecentReposFilePath = original }()
+
+	parent := t.TempDir()
+	parent, _ = filepath.EvalSymlinks(parent)
+	lib := filepath.Join(parent, "libs", "lib")
+	for _, repo := range []string{parent, lib} {
+		if err := exec.Command("git", "init", repo).Run(); err != nil {
+			t.Fatalf("failed to init repository: %v", err)
+		}
+	}
+
+	// Submodules are marked in the file tree.
+	root := BuildTree([]git.FileStatus{{
+		Path: "libs/lib", Index: git.StateUnmodified, Worktree: git.StateModified,
+		Submodule: git.SubmoduleStatus{IsSubmodule: true, CommitChanged: true, HasUntracked: true},
+	}})
+	if lines := root.Render(newTestModel().theme); len(lines) != 2 || !strings.HasSuffix(lines[1], "\tlib (submodule: new commits, untracked content)") {
+		t.Errorf("unexpected file tree lines: %q", lines)
+	}
+
+	// Started in a subdirectory, the git commands run in the root of the work
+	// tree, which the paths of the submodules are relative to.
+	if m := initialModel(filepath.Join(parent, "libs")); m.repoRoot != parent || m.git.RepoPath() != parent {
+		t.Errorf("expected the commands to run in %s, got root %q, path %q", parent, m.repoRoot, m.git.RepoPath())
+	}
+
+	tm := newTestModel()
+	tm.focusedPanel = FilesPanel
+	tm.repoRoot = parent
+	for i := 0; i < 2; i++ {
+		updatedModel, _ := tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+		tm.Model = updatedModel.(Model)
+	}
+	if tm.panels[FilesPanel].tab != submodulesTab {
+		t.Fatalf("expected the Submodules tab, got %v", tm.panels[FilesPanel].tab)
+	}
+
+	submodules := []*git.Submodule{
+		{Name: "lib", Path: "libs/lib", RecordedSHA: "1111111111", CheckedOutSHA: "2222222222", Initialized: true,
+			Status: git.SubmoduleStatus{IsSubmodule: true, HasModified: true}},
+		{Name: "vendor", Path: "vendor", RecordedSHA: "3333333333"},
+	}
+	updatedModel, _ := tm.Update(submodulesUpdatedMsg{submodules: submodules})
+	tm.Model = updatedModel.(Model)
+	lines := tm.panels[FilesPanel].lines
+	if len(lines) != 2 || !strings.Contains(stripAnsi(lines[0]), "libs/lib 1111111 → 2222222 dirty") ||
+		!strings.Contains(stripAnsi(lines[1]), "vendor 3333333 not initialized") {
+		t.Errorf("unexpected submodule lines: %q", lines)
+	}
+
+	// A submodule that is not initialized cannot be opened.
+	tm.panels[FilesPanel].cursor = 1
+	_, cmd := tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(errMsg); !ok {
+		t.Errorf("expected an error for opening an uninitialized submodule, got %#v", msg)
+	}
+
+	// A submodule is opened as a nested session, and escape returns to the parent.
+	tm.panels[FilesPanel].cursor = 0
+	_, cmd = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updatedModel, _ = tm.Update(cmd())
+	tm.Model = updatedModel.(Model)
+	if tm.repoRoot != lib || !reflect.DeepEqual(tm.parentRepos, []string{parent}) {
+		t.Fatalf("expected %s to be opened from %s, got root %q, parents %v", lib, parent, tm.repoRoot, tm.parentRepos)
+	}
+	status := stripAnsi(tm.statusContent("lib", &git.RepoState{Branch: "main"}))
+	if !strings.Contains(status, filepath.Base(parent)+" › lib → main") {
+		t.Errorf("expected the parent repository in the status, got %q", status)
+	}
+
+	_, cmd = tm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	updatedModel, _ = tm.Update(cmd())
+	tm.Model = updatedModel.(Model)
+	if tm.repoRoot != parent || len(tm.parentRepos) != 0 {
+		t.Errorf("expected to return to %s, got root %q, parents %v", parent, tm.repoRoot, tm.parentRepos)
+	}
+}
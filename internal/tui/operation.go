@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// statusUpdatedMsg is sent when the content of the Status panel has been fetched.
+type statusUpdatedMsg struct {
+	content string
+	state   git.RepoState
+}
+
+// isRebasing reports whether a rebase has stopped and is waiting to be continued.
+func (m Model) isRebasing() bool {
+	return m.repoState.Operation == git.OperationRebase
+}
+
+// hasOperationBanner reports whether the Status panel shows the banner of an
+// operation in progress.
+func (m Model) hasOperationBanner() bool {
+	return m.repoState.Operation != git.OperationNone
+}
+
+// handleStatusUpdatedMsg stores the repository state and updates the Status
+// panel, resizing it when the operation banner appears or disappears.
+func (m Model) handleStatusUpdatedMsg(msg statusUpdatedMsg) (tea.Model, tea.Cmd) {
+	hadBanner := m.hasOperationBanner()
+	m.repoState = msg.state
+	if m.hasOperationBanner() != hadBanner {
+		m = m.recalculateLayout()
+	}
+	return m.Update(panelContentUpdatedMsg{panel: StatusPanel, content: msg.content})
+}
+
+// handleOperationKeys handles the keybindings to continue, skip or abort the
+// operation in progress.
+func (m *Model) handleOperationKeys(msg tea.KeyMsg) tea.Cmd {
+	op := m.repoState.Operation
+	switch {
+	case Matches(msg, m.keymap["continue_operation"]) && op.CanContinue():
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.ContinueOperation(op)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+
+	case Matches(msg, m.keymap["skip_operation"]) && op.CanSkip():
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.SkipOperation(op)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+
+	case Matches(msg, m.keymap["abort_operation"]) && op.CanAbort():
+		m.mode = modeConfirm
+		m.confirmMessage = fmt.Sprintf("Abort the %s in progress?", op)
+		m.confirmCallback = func(confirmed bool) tea.Cmd {
+			m.mode = modeNormal
+			if !confirmed {
+				return nil
+			}
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.AbortOperation(op)
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+	}
+	return nil
+}
+
+// statusContent renders the content of the Status panel: the repository and
+// branch, followed by a banner for the operation in progress.
+func (m Model) statusContent(repoName string, state *git.RepoState) string {
+	head := m.theme.BranchCurrent.Render(state.Branch)
+	if state.Detached && state.Operation != git.OperationRebase {
+		head = m.theme.CommitSHA.Render(fmt.Sprintf("(detached at %s)", state.Head))
+	} else if tracking := formatTracking(state.Tracking); tracking != "" {
+		head += " " + m.theme.BranchTracking.Render(tracking)
+	}
+	// Inside a submodule, the repositories it was opened from are shown first.
+	names := make([]string, 0, len(m.parentRepos)+1)
+	for _, parent := range m.parentRepos {
+		names = append(names, filepath.Base(parent))
+	}
+	names = append(names, repoName)
+	content := fmt.Sprintf("%s → %s", m.theme.BranchCurrent.Render(strings.Join(names, " › ")), head)
+
+	if banner := m.operationBanner(state); banner != "" {
+		content += "\n" + banner
+	}
+	return content
+}
+
+// operationBanner describes the operation in progress and the keys to continue,
+// skip or abort it. It returns an empty string if there is no operation.
+func (m Model) operationBanner(state *git.RepoState) string {
+	var description string
+	switch state.Operation {
+	case git.OperationNone:
+		return ""
+	case git.OperationMerge:
+		description = fmt.Sprintf("MERGING %s", state.Target)
+	case git.OperationRebase:
+		description = fmt.Sprintf("REBASING onto %s", state.Target)
+		if state.Total > 0 {
+			description += fmt.Sprintf(" (%d/%d)", state.Step, state.Total)
+		}
+	case git.OperationCherryPick:
+		description = fmt.Sprintf("CHERRY-PICKING %s", state.Target)
+	case git.OperationRevert:
+		description = fmt.Sprintf("REVERTING %s", state.Target)
+	case git.OperationBisect:
+		description = bisectBanner(state)
+	}
+
+	var actions []string
+	if state.Operation.CanContinue() {
+		actions = append(actions, m.actionHint("continue_operation"))
+	}
+	if state.Operation.CanSkip() {
+		actions = append(actions, m.actionHint("skip_operation"))
+	}
+	if state.Operation.CanAbort() {
+		actions = append(actions, m.actionHint("abort_operation"))
+	}
+	return m.theme.GitConflicted.Render(description) + " " + strings.Join(actions, " ")
+}
+
+// actionHint renders the key and description of an action, e.g. "c continue".
+func (m Model) actionHint(action string) string {
+	keys, _ := parseConfiguredKeys(m.keymap[action])
+	return m.theme.HelpKey.Render(helpLabel(keys)) + " " + strings.ToLower(keybindingDescriptions[action])
+}
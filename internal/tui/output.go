@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// outputState holds the state of the command output view in the Main panel,
+// which shows the output of a long-running command while it is produced.
+type outputState struct {
+	title   string
+	lines   []string
+	running bool
+	stream  <-chan outputLine // The output of the command, which tells its messages from stale ones.
+}
+
+// outputTarget is where the output of a running command is shown.
+type outputTarget int
+
+const (
+	// outputToMain shows the output in the output view of the Main panel.
+	outputToMain outputTarget = iota
+	// outputToLog shows the latest line of output at the top of the command log.
+	outputToLog
+)
+
+// outputLineMsg is sent for every line a running command writes.
+type outputLineMsg struct {
+	target  outputTarget
+	line    string
+	replace bool // Whether the line overwrites the previous one, e.g. for progress output.
+	lines   <-chan outputLine
+}
+
+// outputDoneMsg is sent when a command streaming its output has finished.
+type outputDoneMsg struct {
+	target outputTarget
+	cmdStr string
+	err    error
+	lines  <-chan outputLine
+}
+
+// outputLine is a single line written by a running command.
+type outputLine struct {
+	text    string
+	replace bool
+}
+
+// startOutputView runs a command in the background and streams everything it
+// writes into the Main panel.
+func (m *Model) startOutputView(title string, run func(w io.Writer) (string, error)) tea.Cmd {
+	lines, cmd := streamOutput(outputToMain, m.git.Context().Done(), run)
+	m.output = outputState{title: title, running: true, stream: lines}
+	m.mainView = mainViewOutput
+	m.renderOutputView()
+	return cmd
+}
+
+// streamOutput returns a command that runs a command in the background and
+// sends everything it writes line by line to the given target, and the channel
+// of the lines, which the messages of the command carry. Once done is closed,
+// e.g. when the command is cancelled, the rest of the output is dropped.
+func streamOutput(target outputTarget, done <-chan struct{}, run func(w io.Writer) (string, error)) (<-chan outputLine, tea.Cmd) {
+	lines := make(chan outputLine)
+	runCmd := func() tea.Msg {
+		writer := &lineWriter{lines: lines, done: done}
+		cmdStr, err := run(writer)
+		writer.flush()
+		close(lines)
+		return outputDoneMsg{target: target, cmdStr: cmdStr, err: err, lines: lines}
+	}
+	return lines, tea.Batch(runCmd, waitForOutput(target, lines))
+}
+
+// waitForOutput returns a command that waits for the next line of output.
+func waitForOutput(target outputTarget, lines <-chan outputLine) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-lines
+		if !ok {
+			return nil
+		}
+		return outputLineMsg{target: target, line: line.text, replace: line.replace, lines: lines}
+	}
+}
+
+// handleOutputLineMsg appends a line to the output view and waits for the next
+// one. The output is drained even if the view has been closed, or the command
+// is stale, e.g. from the repository open before, so it is never blocked.
+func (m Model) handleOutputLineMsg(msg outputLineMsg) (Model, tea.Cmd) {
+	if msg.lines != m.streamOf(msg.target) {
+		return m, waitForOutput(msg.target, msg.lines)
+	}
+	if msg.target == outputToLog {
+		m.progress.line = msg.line
+		m.renderCommandLog()
+		return m, waitForOutput(msg.target, msg.lines)
+	}
+	if msg.replace && len(m.output.lines) > 0 {
+		m.output.lines[len(m.output.lines)-1] = msg.line
+	} else {
+		m.output.lines = append(m.output.lines, msg.line)
+	}
+	if m.mainView == mainViewOutput {
+		m.renderOutputView()
+	}
+	return m, waitForOutput(msg.target, msg.lines)
+}
+
+// handleOutputDoneMsg marks the output view or the progress in the command log
+// as finished and reports the result.
+func (m Model) handleOutputDoneMsg(msg outputDoneMsg) (Model, tea.Cmd) {
+	if msg.lines != m.streamOf(msg.target) {
+		return m, nil // A stale command, e.g. from the repository open before.
+	}
+	if msg.target == outputToLog {
+		m.progress = progressState{}
+		m.renderCommandLog()
+	} else {
+		m.output.running = false
+		if m.mainView == mainViewOutput {
+			m.renderOutputView()
+		}
+	}
+	if msg.err != nil {
+		return m, func() tea.Msg { return errMsg{msg.err} }
+	}
+	return m, func() tea.Msg { return commandExecutedMsg{msg.cmdStr} }
+}
+
+// streamOf returns the output of the command streaming to target.
+func (m Model) streamOf(target outputTarget) <-chan outputLine {
+	if target == outputToLog {
+		return m.progress.stream
+	}
+	return m.output.stream
+}
+
+// runningCommand returns the title of the command streaming its output, e.g. a
+// push or a bisect run, or an empty string if none is running.
+func (m Model) runningCommand() string {
+	if m.progress.running {
+		return m.progress.title
+	}
+	if m.output.running {
+		return m.output.title
+	}
+	return ""
+}
+
+// renderOutputView renders the collected output into the Main panel and keeps
+// the latest line in view.
+func (m *Model) renderOutputView() {
+	content := strings.Join(m.output.lines, "\n")
+	m.panels[MainPanel].content = content
+	m.panels[MainPanel].viewport.SetContent(content)
+	m.panels[MainPanel].viewport.GotoBottom()
+}
+
+// outputTitle returns the Main panel title while the output view is shown.
+func (m Model) outputTitle() string {
+	if m.output.running {
+		return fmt.Sprintf("%s (running...)", m.output.title)
+	}
+	return m.output.title
+}
+
+// lineWriter is an io.Writer that sends everything written to it line by line
+// to a channel. A carriage return starts a line that replaces the previous one,
+// as git does for progress output.
+type lineWriter struct {
+	lines       chan<- outputLine
+	done        <-chan struct{} // Closed when nobody waits for the lines anymore.
+	buffer      []byte
+	replaceNext bool
+	afterCR     bool
+}
+
+// Write implements io.Writer.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch b {
+		case '\n':
+			if !(w.afterCR && len(w.buffer) == 0) {
+				w.emit()
+			}
+			w.replaceNext = false
+			w.afterCR = false
+		case '\r':
+			w.emit()
+			w.replaceNext = true
+			w.afterCR = true
+		default:
+			w.buffer = append(w.buffer, b)
+			w.afterCR = false
+		}
+	}
+	return len(p), nil
+}
+
+// emit sends the buffered line, or drops it once done is closed.
+func (w *lineWriter) emit() {
+	select {
+	case w.lines <- outputLine{text: string(w.buffer), replace: w.replaceNext}:
+	case <-w.done:
+	}
+	w.buffer = w.buffer[:0]
+}
+
+// flush sends the last line if it was not terminated by a newline.
+func (w *lineWriter) flush() {
+	if len(w.buffer) > 0 {
+		w.emit()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Panel is an enumeration of all the panels in the UI.
@@ -26,12 +27,42 @@ func (p Panel) ID() string {
 	return fmt.Sprintf("panel-%d", p)
 }
 
+// panelTab is one of the views that share the space of a panel.
+type panelTab int
+
+// Defines the available tabs. defaultTab is the panel's own content.
+const (
+	defaultTab panelTab = iota
+	reflogTab
+	remotesTab
+	tagsTab
+	worktreesTab
+	submodulesTab
+)
+
+// panelTabs lists, in order, the tabs of the panels that have more than one.
+var panelTabs = map[Panel][]panelTab{
+	FilesPanel:    {defaultTab, worktreesTab, submodulesTab},
+	BranchesPanel: {defaultTab, remotesTab, tagsTab},
+	CommitsPanel:  {defaultTab, reflogTab},
+}
+
+// tabTitles holds the panel title shown while a tab other than the default is active.
+var tabTitles = map[panelTab]string{
+	reflogTab:     "Reflog",
+	remotesTab:    "Remotes",
+	tagsTab:       "Tags",
+	worktreesTab:  "Worktrees",
+	submodulesTab: "Submodules",
+}
+
 // panel represents the state of a single UI panel.
 type panel struct {
 	viewport viewport.Model
 	content  string
 	lines    []string
 	cursor   int
+	tab      panelTab
 }
 
 // nextPanel shifts focus to the next Panel.
@@ -49,3 +80,26 @@ func (m *Model) prevPanel() {
 	}
 	m.focusedPanel = m.focusedPanel - 1
 }
+
+// switchTab shows the next or previous tab of the focused panel and fetches
+// its content.
+func (m *Model) switchTab(delta int) tea.Cmd {
+	tabs := panelTabs[m.focusedPanel]
+	if len(tabs) < 2 {
+		return nil
+	}
+	p := &m.panels[m.focusedPanel]
+	index := 0
+	for i, tab := range tabs {
+		if tab == p.tab {
+			index = i
+		}
+	}
+	p.tab = tabs[(index+delta+len(tabs))%len(tabs)]
+	p.cursor = 0
+	p.lines = nil
+	p.content = initialContentLoading
+	p.viewport.SetContent(p.content)
+	p.viewport.GotoTop()
+	return m.fetchPanelContent(m.focusedPanel)
+}
@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// rebaseTodoState holds the state of the interactive rebase editor that
+// replaces the commit log in the Commits panel.
+type rebaseTodoState struct {
+	active bool
+	base   string               // The oldest commit being rebased.
+	items  []git.RebaseTodoItem // Newest first, in the order of the Commits panel.
+}
+
+// rebaseTodoLoadedMsg is sent when the commits for the rebase editor have been loaded.
+type rebaseTodoLoadedMsg struct {
+	base  string
+	items []git.RebaseTodoItem // Oldest first, as returned by git.
+	err   error
+}
+
+// rebaseRewordMsg is sent when a new message has been entered for a commit.
+type rebaseRewordMsg struct {
+	sha     string
+	message string
+}
+
+// startRebaseTodo returns a command that loads the commits from base up to HEAD
+// into the rebase editor.
+func (m *Model) startRebaseTodo(base string) tea.Cmd {
+	if m.isRebasing() {
+		return func() tea.Msg {
+			return errMsg{fmt.Errorf("a rebase is already in progress")}
+		}
+	}
+	return func() tea.Msg {
+		items, err := m.git.GetRebaseCommits(base)
+		return rebaseTodoLoadedMsg{base: base, items: items, err: err}
+	}
+}
+
+// handleRebaseTodoLoadedMsg opens the rebase editor with the loaded commits.
+func (m Model) handleRebaseTodoLoadedMsg(msg rebaseTodoLoadedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, func() tea.Msg { return errMsg{msg.err} }
+	}
+	if len(msg.items) == 0 {
+		return m, nil
+	}
+
+	items := make([]git.RebaseTodoItem, len(msg.items))
+	for i, item := range msg.items {
+		items[len(items)-1-i] = item
+	}
+	m.rebaseTodo = rebaseTodoState{active: true, base: msg.base, items: items}
+	m.panels[CommitsPanel].cursor = 0
+	m.panels[CommitsPanel].viewport.GotoTop()
+	m.renderRebaseTodo()
+	return m, m.updateMainPanel()
+}
+
+// handleRebaseRewordMsg stores the new message of a commit marked for rewording.
+func (m Model) handleRebaseRewordMsg(msg rebaseRewordMsg) (Model, tea.Cmd) {
+	if !m.rebaseTodo.active {
+		return m, nil
+	}
+	for i := range m.rebaseTodo.items {
+		if m.rebaseTodo.items[i].SHA == msg.sha {
+			m.rebaseTodo.items[i].Action = git.RebaseReword
+			m.rebaseTodo.items[i].Message = msg.message
+		}
+	}
+	m.renderRebaseTodo()
+	return m, nil
+}
+
+// cancelRebaseTodo closes the rebase editor and restores the commit log.
+func (m *Model) cancelRebaseTodo() tea.Cmd {
+	m.rebaseTodo = rebaseTodoState{}
+	m.panels[CommitsPanel].cursor = 0
+	return m.fetchPanelContent(CommitsPanel)
+}
+
+// handleRebaseTodoKeys handles keybindings while the rebase editor is shown.
+func (m *Model) handleRebaseTodoKeys(msg tea.KeyMsg) tea.Cmd {
+	if handled, cmd := m.handleCursorMovement(msg); handled {
+		return cmd
+	}
+
+	cursor := m.panels[CommitsPanel].cursor
+	if cursor >= len(m.rebaseTodo.items) {
+		return nil
+	}
+	item := &m.rebaseTodo.items[cursor]
+
+	switch {
+	case Matches(msg, m.keymap["rebase_pick"]):
+		m.setRebaseAction(item, git.RebasePick)
+	case Matches(msg, m.keymap["rebase_edit"]):
+		m.setRebaseAction(item, git.RebaseEdit)
+	case Matches(msg, m.keymap["rebase_squash"]):
+		m.setRebaseAction(item, git.RebaseSquash)
+	case Matches(msg, m.keymap["rebase_fixup"]):
+		m.setRebaseAction(item, git.RebaseFixup)
+	case Matches(msg, m.keymap["rebase_drop"]):
+		m.setRebaseAction(item, git.RebaseDrop)
+
+	case Matches(msg, m.keymap["rebase_reword"]):
+		sha, subject := item.SHA, item.Subject
+		if item.Message != "" {
+			subject = item.Message
+		}
+		m.mode = modeInput
+		m.promptTitle = fmt.Sprintf("New message for %s", sha)
+		m.textInput.SetValue(subject)
+		m.textInput.Focus()
+		m.inputCallback = func(message string) tea.Cmd {
+			if message == "" {
+				return nil
+			}
+			return func() tea.Msg {
+				return rebaseRewordMsg{sha: sha, message: message}
+			}
+		}
+
+	case Matches(msg, m.keymap["rebase_move_down"]):
+		if cursor < len(m.rebaseTodo.items)-1 {
+			m.moveRebaseTodoItem(cursor, cursor+1)
+		}
+
+	case Matches(msg, m.keymap["rebase_move_up"]):
+		if cursor > 0 {
+			m.moveRebaseTodoItem(cursor, cursor-1)
+		}
+
+	case Matches(msg, m.keymap["rebase_start"]):
+		base := m.rebaseTodo.base
+		todo := make([]git.RebaseTodoItem, len(m.rebaseTodo.items))
+		for i, item := range m.rebaseTodo.items {
+			todo[len(todo)-1-i] = item
+		}
+		m.rebaseTodo = rebaseTodoState{}
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.InteractiveRebase(base, todo)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return nil
+}
+
+// setRebaseAction marks a todo item with the given action.
+func (m *Model) setRebaseAction(item *git.RebaseTodoItem, action git.RebaseAction) {
+	item.Action = action
+	item.Message = ""
+	m.renderRebaseTodo()
+}
+
+// moveRebaseTodoItem swaps the todo item at from with the one at to and moves
+// the cursor along with it.
+func (m *Model) moveRebaseTodoItem(from, to int) {
+	items := m.rebaseTodo.items
+	items[from], items[to] = items[to], items[from]
+
+	p := &m.panels[CommitsPanel]
+	p.cursor = to
+	if p.cursor < p.viewport.YOffset {
+		p.viewport.SetYOffset(p.cursor)
+	}
+	if p.cursor >= p.viewport.YOffset+p.viewport.Height {
+		p.viewport.SetYOffset(p.cursor - p.viewport.Height + 1)
+	}
+	m.renderRebaseTodo()
+}
+
+// renderRebaseTodo renders the todo items into the lines of the Commits panel.
+func (m *Model) renderRebaseTodo() {
+	lines := make([]string, len(m.rebaseTodo.items))
+	for i, item := range m.rebaseTodo.items {
+		action := item.Action
+		if action == "" {
+			action = git.RebasePick
+		}
+		subject := item.Subject
+		if action == git.RebaseReword && item.Message != "" {
+			subject = item.Message
+		}
+		styledAction := m.rebaseActionStyle(action).Render(fmt.Sprintf("%-6s", action))
+		if action == git.RebaseDrop {
+			subject = m.theme.GitUntracked.Render(subject)
+		}
+		lines[i] = fmt.Sprintf("%s\t%s\t%s", styledAction, m.theme.CommitSHA.Render(item.SHA), subject)
+	}
+	m.panels[CommitsPanel].lines = lines
+}
+
+// rebaseActionStyle returns the style used for an action in the rebase editor.
+func (m Model) rebaseActionStyle(action git.RebaseAction) lipgloss.Style {
+	switch action {
+	case git.RebaseReword, git.RebaseEdit:
+		return m.theme.DiffHunk
+	case git.RebaseSquash, git.RebaseFixup:
+		return m.theme.CommitMerge
+	case git.RebaseDrop:
+		return m.theme.DiffRemoved
+	}
+	return m.theme.DiffAdded
+}
+
+// selectedRebaseTodoSHA returns the commit under the cursor of the rebase editor.
+func (m Model) selectedRebaseTodoSHA() string {
+	cursor := m.panels[CommitsPanel].cursor
+	if cursor < 0 || cursor >= len(m.rebaseTodo.items) {
+		return ""
+	}
+	return m.rebaseTodo.items[cursor].SHA
+}
+
+// commitsTitle returns the title of the Commits panel, which reflects the
+// state of an interactive rebase.
+func (m Model) commitsTitle() string {
+	switch {
+	case m.rebaseTodo.active:
+		return panelFour + " (rebase todo)"
+	case m.isRebasing():
+		return panelFour + " (rebasing)"
+	case len(m.copiedCommits) > 0:
+		return fmt.Sprintf("%s (%d copied)", panelFour, len(m.copiedCommits))
+	case m.fileHistory.path != "":
+		return fmt.Sprintf("%s (history of %s)", panelFour, m.fileHistory.path)
+	}
+	return panelFour
+}
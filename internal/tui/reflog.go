@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// undoPlannedMsg is sent when the action to undo or redo has been found in the
+// reflog.
+type undoPlannedMsg struct {
+	move  git.HeadMove
+	redo  bool
+	dirty bool // Whether the working tree has uncommitted changes.
+}
+
+// reflogContent renders the lines of the Reflog tab of the Commits panel as
+// "selector\tsha\tsubject", so that the commit is in the same column as in
+// the Commits tab.
+func (m Model) reflogContent() (string, error) {
+	entries, err := m.git.GetReflog()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "No reflog entries.", nil
+	}
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = fmt.Sprintf("%s\t%s\t%s", entry.Selector, entry.SHA, entry.Subject())
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// handleReflogKeys handles the keybindings of the Reflog tab of the Commits panel.
+func (m *Model) handleReflogKeys(msg tea.KeyMsg) tea.Cmd {
+	if handled, cmd := m.handleCursorMovement(msg); handled {
+		return cmd
+	}
+	if m.panels[CommitsPanel].cursor >= len(m.panels[CommitsPanel].lines) {
+		return nil
+	}
+	sha := commitLineSHA(m.panels[CommitsPanel].lines[m.panels[CommitsPanel].cursor])
+	if sha == "" {
+		return nil
+	}
+
+	switch {
+	case Matches(msg, m.keymap["copy_commit"]):
+		m.toggleCopiedCommit(sha)
+
+	case Matches(msg, m.keymap["reset_to_commit"]):
+		m.mode = modeConfirm
+		m.confirmMessage = fmt.Sprintf("Hard reset to commit %s? This will discard all changes!", sha)
+		m.confirmCallback = func(confirmed bool) tea.Cmd {
+			m.mode = modeNormal
+			if !confirmed {
+				return nil
+			}
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.ResetToCommit(sha)
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+	}
+	return nil
+}
+
+// planUndo returns a command that finds the action to undo, or to redo, in the
+// reflog.
+func (m *Model) planUndo(redo bool) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := m.git.GetReflog()
+		if err != nil {
+			return errMsg{err}
+		}
+		move := git.FindUndo(entries)
+		if redo {
+			move = git.FindRedo(entries)
+		}
+		if move == nil {
+			if redo {
+				return errMsg{fmt.Errorf("nothing to redo")}
+			}
+			return errMsg{fmt.Errorf("nothing to undo")}
+		}
+
+		status, err := m.git.GetRepoStatus()
+		if err != nil {
+			return errMsg{err}
+		}
+		return undoPlannedMsg{move: *move, redo: redo, dirty: status.HasTrackedChanges()}
+	}
+}
+
+// handleUndoPlannedMsg undoes or redoes the planned action, asking for
+// confirmation first if it would discard uncommitted changes.
+func (m Model) handleUndoPlannedMsg(msg undoPlannedMsg) (Model, tea.Cmd) {
+	gc := m.git
+	run := func() tea.Msg {
+		_, cmdStr, err := gc.UndoHeadMove(msg.move, msg.redo)
+		if err != nil {
+			return errMsg{err}
+		}
+		return commandExecutedMsg{cmdStr}
+	}
+	if !msg.dirty || !msg.move.DiscardsChanges() {
+		return m, run
+	}
+
+	verb := "Undo"
+	if msg.redo {
+		verb = "Redo"
+	}
+	m.mode = modeConfirm
+	m.confirmMessage = fmt.Sprintf("%s %q? This will discard all uncommitted changes!", verb, msg.move.Action)
+	m.confirmCallback = func(confirmed bool) tea.Cmd {
+		if !confirmed {
+			return nil
+		}
+		return run
+	}
+	return m, nil
+}
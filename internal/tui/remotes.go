@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// remotesState holds the state of the Remotes tab of the Branches panel.
+type remotesState struct {
+	// expanded holds the names of the remotes whose branches are listed. It
+	// is replaced rather than modified, as it is read by running commands.
+	expanded []string
+	items    []remoteItem // The item of each line of the tab.
+}
+
+// remoteItem is a line of the Remotes tab: a remote, or one of its
+// remote-tracking branches if branch is set.
+type remoteItem struct {
+	remote *git.Remote
+	branch *git.RemoteBranch
+}
+
+// key identifies the item across refreshes.
+func (i remoteItem) key() string {
+	if i.branch != nil {
+		return i.branch.FullName()
+	}
+	return i.remote.Name
+}
+
+// remotesUpdatedMsg is sent when the remotes have been fetched.
+type remotesUpdatedMsg struct {
+	remotes []*git.Remote
+}
+
+// remoteAddURLMsg is sent when the name of a new remote has been entered.
+type remoteAddURLMsg struct {
+	name string
+}
+
+// fetchRemotes lists the remotes, with the branches of the expanded ones.
+func (m Model) fetchRemotes() ([]*git.Remote, error) {
+	remotes, err := m.git.GetRemotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, remote := range remotes {
+		if !m.isRemoteExpanded(remote.Name) {
+			continue
+		}
+		if remote.Branches, err = m.git.GetRemoteBranches(remote.Name); err != nil {
+			return nil, err
+		}
+	}
+	return remotes, nil
+}
+
+// isRemoteExpanded reports whether the branches of a remote are listed.
+func (m Model) isRemoteExpanded(name string) bool {
+	for _, expanded := range m.remotes.expanded {
+		if expanded == name {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleRemote lists or hides the branches of a remote.
+func (m *Model) toggleRemote(name string) tea.Cmd {
+	expanded := make([]string, 0, len(m.remotes.expanded)+1)
+	for _, remote := range m.remotes.expanded {
+		if remote != name {
+			expanded = append(expanded, remote)
+		}
+	}
+	if len(expanded) == len(m.remotes.expanded) {
+		expanded = append(expanded, name)
+	}
+	m.remotes.expanded = expanded
+	return m.fetchPanelContent(BranchesPanel)
+}
+
+// handleRemotesUpdatedMsg renders the remotes into the Remotes tab, keeping
+// the cursor on the selected item.
+func (m Model) handleRemotesUpdatedMsg(msg remotesUpdatedMsg) (Model, tea.Cmd) {
+	if m.panels[BranchesPanel].tab != remotesTab {
+		return m, nil
+	}
+	var selected string
+	if item := m.selectedRemoteItem(); item != nil {
+		selected = item.key()
+	}
+
+	var items []remoteItem
+	var lines []string
+	for _, remote := range msg.remotes {
+		icon := dirCollapsedIcon
+		if m.isRemoteExpanded(remote.Name) {
+			icon = dirExpandedIcon
+		}
+		items = append(items, remoteItem{remote: remote})
+		lines = append(lines, fmt.Sprintf("%s%s %s", icon, m.theme.BranchCurrent.Render(remote.Name), remote.FetchURL))
+		for _, branch := range remote.Branches {
+			items = append(items, remoteItem{remote: remote, branch: branch})
+			lines = append(lines, fmt.Sprintf("%s%s %s", treePrefix, m.theme.BranchDate.Render(branch.LastCommit), branch.FullName()))
+		}
+	}
+	if len(lines) == 0 {
+		lines = []string{"No remotes."}
+	}
+
+	cursor := 0
+	for i, item := range items {
+		if item.key() == selected {
+			cursor = i
+			break
+		}
+	}
+	m.remotes.items = items
+	content := strings.Join(lines, "\n")
+	m.panels[BranchesPanel].lines = lines
+	m.panels[BranchesPanel].content = content
+	m.panels[BranchesPanel].viewport.SetContent(content)
+	m.panels[BranchesPanel].cursor = cursor
+	return m, m.updateMainPanel()
+}
+
+// selectedRemoteItem returns the item under the cursor of the Remotes tab, or
+// nil if there is none.
+func (m Model) selectedRemoteItem() *remoteItem {
+	cursor := m.panels[BranchesPanel].cursor
+	if cursor < 0 || cursor >= len(m.remotes.items) {
+		return nil
+	}
+	return &m.remotes.items[cursor]
+}
+
+// remoteDetails renders the Main panel content for the selected item of the
+// Remotes tab.
+func (m Model) remoteDetails() (string, error) {
+	item := m.selectedRemoteItem()
+	if item == nil {
+		return "", nil
+	}
+	if item.branch != nil {
+		return m.git.ShowLog(git.LogOptions{Graph: true, Color: "always", Branch: item.branch.FullName()})
+	}
+	remote := item.remote
+	details := fmt.Sprintf("Remote: %s\nFetch URL: %s\nPush URL: %s",
+		m.theme.BranchCurrent.Render(remote.Name), remote.FetchURL, remote.PushURL)
+	return details, nil
+}
+
+// handleRemotesKeys handles the keybindings of the Remotes tab of the Branches panel.
+func (m *Model) handleRemotesKeys(msg tea.KeyMsg) tea.Cmd {
+	if handled, cmd := m.handleCursorMovement(msg); handled {
+		return cmd
+	}
+	if Matches(msg, m.keymap["add_remote"]) {
+		return m.promptAddRemote()
+	}
+
+	item := m.selectedRemoteItem()
+	if item == nil {
+		return nil
+	}
+	name := item.remote.Name
+
+	switch {
+	case Matches(msg, m.keymap["fetch"]):
+		return m.fetchRemote(name)
+
+	case Matches(msg, m.keymap["checkout"]):
+		if item.branch == nil {
+			return m.toggleRemote(name)
+		}
+		return m.promptCheckoutRemoteBranch(item.branch)
+
+	case Matches(msg, m.keymap["remove_remote"]):
+		m.mode = modeConfirm
+		m.confirmMessage = fmt.Sprintf("Remove remote %s and its remote-tracking branches?", name)
+		m.confirmCallback = func(confirmed bool) tea.Cmd {
+			m.mode = modeNormal
+			if !confirmed {
+				return nil
+			}
+			return m.manageRemote(git.RemoteOptions{Remove: true, Name: name})
+		}
+
+	case Matches(msg, m.keymap["rename_remote"]):
+		m.mode = modeInput
+		m.promptTitle = "New Remote Name"
+		m.textInput.SetValue(name)
+		m.textInput.Focus()
+		m.inputCallback = func(input string) tea.Cmd {
+			m.mode = modeNormal
+			if input == "" || input == name {
+				return nil
+			}
+			return m.manageRemote(git.RemoteOptions{Rename: true, Name: name, NewName: input})
+		}
+
+	case Matches(msg, m.keymap["edit_remote_url"]):
+		url := item.remote.FetchURL
+		m.mode = modeInput
+		m.promptTitle = fmt.Sprintf("URL of %s", name)
+		m.textInput.SetValue(url)
+		m.textInput.Focus()
+		m.inputCallback = func(input string) tea.Cmd {
+			m.mode = modeNormal
+			if input == "" || input == url {
+				return nil
+			}
+			return m.manageRemote(git.RemoteOptions{SetURL: true, Name: name, URL: input})
+		}
+	}
+	return nil
+}
+
+// promptAddRemote asks for the name of a new remote, then for its URL.
+func (m *Model) promptAddRemote() tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = "New Remote Name"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg { return remoteAddURLMsg{name: input} }
+	}
+	return nil
+}
+
+// handleRemoteAddURLMsg asks for the URL of the new remote.
+func (m Model) handleRemoteAddURLMsg(msg remoteAddURLMsg) (Model, tea.Cmd) {
+	m.mode = modeInput
+	m.promptTitle = fmt.Sprintf("URL of %s", msg.name)
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	gc := m.git
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg {
+			_, cmdStr, err := gc.ManageRemote(git.RemoteOptions{Add: true, Name: msg.name, URL: input})
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return m, nil
+}
+
+// promptCheckoutRemoteBranch asks for the name of a local branch that tracks
+// the remote-tracking branch, and checks it out.
+func (m *Model) promptCheckoutRemoteBranch(branch *git.RemoteBranch) tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = fmt.Sprintf("Local Branch Tracking %s", branch.FullName())
+	m.textInput.SetValue(branch.Name)
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		m.mode = modeNormal
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.CheckoutRemoteBranch(branch, input)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return nil
+}
+
+// manageRemote returns a command that runs ManageRemote with the given options.
+func (m *Model) manageRemote(options git.RemoteOptions) tea.Cmd {
+	return func() tea.Msg {
+		_, cmdStr, err := m.git.ManageRemote(options)
+		if err != nil {
+			return errMsg{err}
+		}
+		return commandExecutedMsg{cmdStr}
+	}
+}
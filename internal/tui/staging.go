@@ -0,0 +1,309 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// stagingState holds the state of the hunk staging view in the Main panel.
+type stagingState struct {
+	path   string
+	cached bool // Whether the staged (HEAD vs index) diff is shown.
+	diff   *git.FileDiff
+	hunk   int // Index of the selected hunk.
+
+	// Line selection mode within the selected hunk.
+	lineMode bool
+	line     int          // Index of the cursor line within the hunk.
+	selected map[int]bool // Indices of the lines picked for staging.
+}
+
+// stagingDiffMsg is sent when the diff for the staging view has been loaded.
+type stagingDiffMsg struct {
+	path   string
+	cached bool
+	diff   *git.FileDiff
+	err    error
+}
+
+// enterStagingView switches the Main panel to the hunk staging view for the
+// given file and moves focus to it.
+func (m *Model) enterStagingView(file git.FileStatus) tea.Cmd {
+	if file.IsUntracked() {
+		return nil
+	}
+	m.staging = stagingState{path: file.Path, cached: !file.HasUnstagedChanges()}
+	m.mainView = mainViewStaging
+	m.focusedPanel = MainPanel
+	return m.loadStagingDiff()
+}
+
+// escapeMainView leaves the innermost level of the dedicated Main panel view.
+func (m *Model) escapeMainView() tea.Cmd {
+	if m.mainView == mainViewStaging && m.staging.lineMode {
+		m.staging.lineMode = false
+		m.staging.selected = nil
+		m.renderStagingView()
+		return nil
+	}
+	return m.exitMainView()
+}
+
+// exitMainView returns the Main panel to its default view and gives focus back
+// to the panel the view was opened from.
+func (m *Model) exitMainView() tea.Cmd {
+	m.mainView = mainViewDiff
+	m.staging = stagingState{}
+	m.conflicts = conflictState{}
+	m.blame = blameState{}
+	m.focusedPanel = m.activeSourcePanel
+	*m = m.recalculateLayout()
+	m.panels[MainPanel].viewport.GotoTop()
+	return m.updateMainPanel()
+}
+
+// loadStagingDiff returns a command that fetches the diff shown by the staging view.
+// If the requested side has no changes left, the other side is loaded instead.
+func (m *Model) loadStagingDiff() tea.Cmd {
+	path, cached := m.staging.path, m.staging.cached
+	return func() tea.Msg {
+		diff, err := m.git.GetFileDiff(path, cached)
+		if err == nil && len(diff.Hunks) == 0 {
+			cached = !cached
+			diff, err = m.git.GetFileDiff(path, cached)
+		}
+		return stagingDiffMsg{path: path, cached: cached, diff: diff, err: err}
+	}
+}
+
+// handleStagingDiffMsg stores a freshly loaded diff and re-renders the staging view.
+func (m Model) handleStagingDiffMsg(msg stagingDiffMsg) (Model, tea.Cmd) {
+	if m.mainView != mainViewStaging || msg.path != m.staging.path {
+		return m, nil // Stale message.
+	}
+	if msg.err != nil || len(msg.diff.Hunks) == 0 {
+		// The file no longer has any hunks to stage or unstage.
+		return m, m.exitMainView()
+	}
+
+	m.staging.cached = msg.cached
+	m.staging.diff = msg.diff
+	if m.staging.hunk >= len(msg.diff.Hunks) {
+		m.staging.hunk = len(msg.diff.Hunks) - 1
+	}
+	if m.staging.lineMode {
+		// The hunk has changed, so any previous selection is meaningless now.
+		m.staging.selected = map[int]bool{}
+		m.staging.line = m.nearestChangedLine(m.staging.line, 1)
+	}
+	m.renderStagingView()
+	return m, nil
+}
+
+// handleStagingKeys handles keybindings while the staging view is shown.
+func (m *Model) handleStagingKeys(msg tea.KeyMsg) tea.Cmd {
+	if m.staging.diff == nil {
+		return nil
+	}
+	if m.staging.lineMode {
+		return m.handleStagingLineKeys(msg)
+	}
+
+	switch {
+	case Matches(msg, m.keymap["up"]):
+		if m.staging.hunk > 0 {
+			m.staging.hunk--
+			m.renderStagingView()
+		}
+
+	case Matches(msg, m.keymap["down"]):
+		if m.staging.hunk < len(m.staging.diff.Hunks)-1 {
+			m.staging.hunk++
+			m.renderStagingView()
+		}
+
+	case Matches(msg, m.keymap["toggle_staged"]):
+		m.staging.cached = !m.staging.cached
+		m.staging.hunk = 0
+		return m.loadStagingDiff()
+
+	case Matches(msg, m.keymap["toggle_line_mode"]):
+		m.staging.lineMode = true
+		m.staging.selected = map[int]bool{}
+		m.staging.line = m.nearestChangedLine(0, 1)
+		m.renderStagingView()
+
+	case Matches(msg, m.keymap["stage_hunk"]):
+		patch := m.staging.diff.HunkPatch(m.staging.hunk)
+		// Unstaging applies the staged hunk to the index in reverse.
+		reverse := m.staging.cached
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.ApplyPatch(patch, git.ApplyOptions{Cached: true, Reverse: reverse})
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return nil
+}
+
+// handleStagingLineKeys handles keybindings while selecting single lines of a hunk.
+func (m *Model) handleStagingLineKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case Matches(msg, m.keymap["up"]):
+		m.staging.line = m.nearestChangedLine(m.staging.line-1, -1)
+		m.renderStagingView()
+
+	case Matches(msg, m.keymap["down"]):
+		m.staging.line = m.nearestChangedLine(m.staging.line+1, 1)
+		m.renderStagingView()
+
+	case Matches(msg, m.keymap["toggle_line_mode"]):
+		m.staging.lineMode = false
+		m.staging.selected = nil
+		m.renderStagingView()
+
+	case Matches(msg, m.keymap["select_line"]):
+		if isChangedLine(m.stagingHunk().Lines, m.staging.line) {
+			m.staging.selected[m.staging.line] = !m.staging.selected[m.staging.line]
+			m.renderStagingView()
+		}
+
+	case Matches(msg, m.keymap["apply_lines"]):
+		selected := m.staging.selected
+		if !hasSelection(selected) {
+			// Without an explicit selection, act on the cursor line.
+			selected = map[int]bool{m.staging.line: true}
+		}
+		reverse := m.staging.cached
+		patch := m.staging.diff.LinesPatch(m.staging.hunk, selected, reverse)
+		if patch == "" {
+			return nil
+		}
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.ApplyPatch(patch, git.ApplyOptions{Cached: true, Reverse: reverse})
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return nil
+}
+
+// stagingHunk returns the selected hunk of the staging view.
+func (m Model) stagingHunk() git.Hunk {
+	return m.staging.diff.Hunks[m.staging.hunk]
+}
+
+// nearestChangedLine returns the index of the first '+' or '-' line of the
+// selected hunk, starting at from and moving in direction step. If there is
+// none in that direction, the current cursor line is kept.
+func (m Model) nearestChangedLine(from, step int) int {
+	lines := m.stagingHunk().Lines
+	for i := from; i >= 0 && i < len(lines); i += step {
+		if isChangedLine(lines, i) {
+			return i
+		}
+	}
+	if isChangedLine(lines, m.staging.line) {
+		return m.staging.line
+	}
+	// The cursor is not on a changed line (e.g. the hunk was reloaded), so
+	// search the whole hunk instead.
+	for i := range lines {
+		if isChangedLine(lines, i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// isChangedLine reports whether the line at index i is an addition or removal.
+func isChangedLine(lines []string, i int) bool {
+	return i >= 0 && i < len(lines) && (strings.HasPrefix(lines[i], "+") || strings.HasPrefix(lines[i], "-"))
+}
+
+// hasSelection reports whether any line is selected.
+func hasSelection(selected map[int]bool) bool {
+	for _, ok := range selected {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// renderStagingView renders the hunks of the staging diff into the Main panel,
+// marking the selected hunk and scrolling it into view.
+func (m *Model) renderStagingView() {
+	var builder strings.Builder
+	lineCount := 0
+	selectedOffset := 0
+	width := m.panels[MainPanel].viewport.Width - 1
+
+	for i, hunk := range m.staging.diff.Hunks {
+		isSelectedHunk := i == m.staging.hunk
+		gutter := " "
+		if isSelectedHunk {
+			gutter = m.theme.ActiveBorder.Style.Render(hunkSelectedGutter)
+			selectedOffset = lineCount
+		}
+
+		builder.WriteString(gutter + m.theme.DiffHunk.Render(hunk.Header()) + "\n")
+		lineCount++
+		for j, line := range hunk.Lines {
+			lineGutter := gutter
+			styledLine := styleDiffLine(line, m.theme)
+			if isSelectedHunk && m.staging.lineMode {
+				if m.staging.selected[j] {
+					lineGutter = m.theme.ActiveBorder.Style.Render(lineSelectedGutter)
+				}
+				if j == m.staging.line {
+					styledLine = m.theme.SelectedLine.Width(width).Render(line)
+					// Keep the cursor line visible in long hunks.
+					selectedOffset = lineCount - m.panels[MainPanel].viewport.Height/2
+				}
+			}
+			builder.WriteString(lineGutter + styledLine + "\n")
+			lineCount++
+		}
+	}
+
+	content := strings.TrimRight(builder.String(), "\n")
+	m.panels[MainPanel].content = content
+	m.panels[MainPanel].viewport.SetContent(content)
+	m.panels[MainPanel].viewport.SetYOffset(selectedOffset)
+}
+
+// stagingTitle returns the Main panel title while the staging view is shown.
+func (m Model) stagingTitle() string {
+	side := "Unstaged"
+	if m.staging.cached {
+		side = "Staged"
+	}
+	if m.staging.diff == nil {
+		return fmt.Sprintf("%s: %s", side, m.staging.path)
+	}
+	if m.staging.lineMode {
+		return fmt.Sprintf("%s: %s (select lines in hunk %d/%d)", side, m.staging.path, m.staging.hunk+1, len(m.staging.diff.Hunks))
+	}
+	return fmt.Sprintf("%s: %s (hunk %d/%d)", side, m.staging.path, m.staging.hunk+1, len(m.staging.diff.Hunks))
+}
+
+// styleDiffLine colors a single line of a diff body based on its prefix.
+func styleDiffLine(line string, theme Theme) string {
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return theme.DiffAdded.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return theme.DiffRemoved.Render(line)
+	case strings.HasPrefix(line, "\\"):
+		return theme.GitUntracked.Render(line)
+	}
+	return line
+}
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// submodulesUpdatedMsg is sent when the submodules have been fetched.
+type submodulesUpdatedMsg struct {
+	submodules []*git.Submodule
+}
+
+// handleSubmodulesUpdatedMsg renders the submodules into the Submodules tab of
+// the Files panel.
+func (m Model) handleSubmodulesUpdatedMsg(msg submodulesUpdatedMsg) (Model, tea.Cmd) {
+	if m.panels[FilesPanel].tab != submodulesTab {
+		return m, nil
+	}
+
+	lines := make([]string, len(msg.submodules))
+	for i, s := range msg.submodules {
+		line := fmt.Sprintf("%s %s", m.theme.BranchCurrent.Render(s.Path), m.theme.CommitSHA.Render(shortSHA(s.RecordedSHA)))
+		if s.OutOfDate() {
+			line += " → " + m.theme.CommitSHA.Render(shortSHA(s.CheckedOutSHA))
+		}
+		if !s.Initialized {
+			line += " " + m.theme.BranchDate.Render("not initialized")
+		}
+		if s.Conflict {
+			line += " " + m.theme.ErrorText.Render("conflict")
+		}
+		if s.Status.HasModified || s.Status.HasUntracked {
+			line += " " + m.theme.ErrorText.Render("dirty")
+		}
+		lines[i] = line
+	}
+	if len(lines) == 0 {
+		lines = []string{"No submodules."}
+	}
+
+	m.submodules = msg.submodules
+	content := strings.Join(lines, "\n")
+	m.panels[FilesPanel].lines = lines
+	m.panels[FilesPanel].content = content
+	m.panels[FilesPanel].viewport.SetContent(content)
+	if m.panels[FilesPanel].cursor >= len(lines) {
+		m.panels[FilesPanel].cursor = len(lines) - 1
+	}
+	return m, m.updateMainPanel()
+}
+
+// selectedSubmodule returns the submodule under the cursor of the Submodules
+// tab, or nil if there is none.
+func (m Model) selectedSubmodule() *git.Submodule {
+	cursor := m.panels[FilesPanel].cursor
+	if m.panels[FilesPanel].tab != submodulesTab || cursor < 0 || cursor >= len(m.submodules) {
+		return nil
+	}
+	return m.submodules[cursor]
+}
+
+// submoduleDetails describes the selected submodule for the Main panel, with
+// the commits between the recorded and the checked out commit, if they differ,
+// and the log of the submodule.
+func (m Model) submoduleDetails() (string, error) {
+	s := m.selectedSubmodule()
+	if s == nil {
+		return "", nil
+	}
+	details := fmt.Sprintf("Submodule: %s\nPath: %s\n", m.theme.BranchCurrent.Render(s.Name), s.Path)
+	if s.URL != "" {
+		details += fmt.Sprintf("URL: %s\n", s.URL)
+	}
+	details += fmt.Sprintf("Recorded: %s\n", m.theme.CommitSHA.Render(s.RecordedSHA))
+	if !s.Initialized {
+		return details + "Not initialized: init and update it to check it out.\n", nil
+	}
+	details += fmt.Sprintf("Checked out: %s\n", m.theme.CommitSHA.Render(s.CheckedOutSHA))
+	if summary := s.Status.Summary(); summary != "" {
+		details += fmt.Sprintf("Changes: %s\n", summary)
+	}
+
+	if s.OutOfDate() {
+		diff, err := m.git.ShowDiff(git.DiffOptions{Color: true, Submodule: "log", Commit1: s.Path})
+		if err != nil {
+			return details, err
+		}
+		details += "\n" + diff
+	}
+	log, err := m.submoduleGit(s.Path).ShowLog(git.LogOptions{Graph: true, Color: "always", Branch: "HEAD"})
+	if err != nil {
+		return details, err
+	}
+	return details + "\n" + log, nil
+}
+
+// submoduleGit returns the git commands for the submodule at path.
+func (m Model) submoduleGit(path string) *git.GitCommands {
+	return m.git.WithRepoPath(filepath.Join(m.repoRoot, path))
+}
+
+// openSubmodule opens the submodule at path in place of the repository, which
+// escape returns to.
+func (m Model) openSubmodule(path string) tea.Cmd {
+	parents := append(m.parentRepos[:len(m.parentRepos):len(m.parentRepos)], m.repoRoot)
+	return openNestedRepo(filepath.Join(m.repoRoot, path), parents)
+}
+
+// returnToParentRepo opens the repository the submodule was opened from.
+func (m Model) returnToParentRepo() tea.Cmd {
+	n := len(m.parentRepos)
+	if n == 0 {
+		return nil
+	}
+	return openNestedRepo(m.parentRepos[n-1], m.parentRepos[:n-1])
+}
+
+// handleSubmodulesKeys handles the keybindings of the Submodules tab of the
+// Files panel.
+func (m *Model) handleSubmodulesKeys(msg tea.KeyMsg) tea.Cmd {
+	if handled, cmd := m.handleCursorMovement(msg); handled {
+		return cmd
+	}
+
+	s := m.selectedSubmodule()
+	if s == nil {
+		return nil
+	}
+	path := s.Path
+
+	switch {
+	case Matches(msg, m.keymap["checkout"]):
+		if !s.Initialized {
+			return func() tea.Msg { return errMsg{fmt.Errorf("submodule %s is not initialized", path)} }
+		}
+		return m.openSubmodule(path)
+
+	case Matches(msg, m.keymap["init_submodule"]):
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.InitSubmodules(path)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+
+	case Matches(msg, m.keymap["update_submodule"]):
+		// An uninitialized submodule is initialized, as update would skip it.
+		options := git.SubmoduleUpdateOptions{Paths: []string{path}, Init: !s.Initialized, Recursive: true}
+		return m.startRemoteCommand("git submodule update "+path, func(w io.Writer) (string, error) {
+			options.Progress = w
+			_, cmdStr, err := m.git.UpdateSubmodules(options)
+			return cmdStr, err
+		})
+
+	case Matches(msg, m.keymap["sync_submodule"]):
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.SyncSubmodules(path)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return nil
+}
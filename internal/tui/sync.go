@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// progressState holds the progress of the remote command running in the
+// background, which is shown at the top of the command log.
+type progressState struct {
+	running bool
+	title   string
+	line    string            // The latest line of progress output.
+	stream  <-chan outputLine // The output of the command, which tells its messages from stale ones.
+}
+
+// panelBindsSyncKeys reports whether the focused panel uses the keys to push,
+// pull or fetch for its own actions.
+func (m Model) panelBindsSyncKeys() bool {
+	switch m.focusedPanel {
+	case StashPanel:
+		return true
+	case BranchesPanel:
+		return m.panels[BranchesPanel].tab != defaultTab
+	case CommitsPanel:
+		return m.rebaseTodo.active
+	}
+	return false
+}
+
+// publishBranchMsg is sent when the remote to publish a branch to has been
+// entered.
+type publishBranchMsg struct {
+	branch string
+	remote string
+}
+
+// handleSyncKeys handles the keybindings to push, pull and fetch, which are
+// available in every panel that does not use the keys for its own actions.
+func (m *Model) handleSyncKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case Matches(msg, m.keymap["push"]):
+		if state := m.repoState; state.Branch != "" && !state.Detached && state.Tracking.Upstream == "" {
+			return m.promptPublishBranch(state.Branch)
+		}
+		return m.startRemoteCommand("git push", func(w io.Writer) (string, error) {
+			_, cmdStr, err := m.git.Push(git.PushOptions{Progress: w})
+			return cmdStr, err
+		})
+	case Matches(msg, m.keymap["pull"]):
+		return m.startRemoteCommand("git pull", func(w io.Writer) (string, error) {
+			_, cmdStr, err := m.git.Pull(git.PullOptions{Progress: w})
+			return cmdStr, err
+		})
+	case Matches(msg, m.keymap["fetch"]):
+		return m.fetchRemote("")
+	}
+	return nil
+}
+
+// fetchRemote fetches the given remote, or all remotes if remote is empty.
+func (m *Model) fetchRemote(remote string) tea.Cmd {
+	title := "git fetch --all"
+	if remote != "" {
+		title = "git fetch " + remote
+	}
+	return m.startRemoteCommand(title, func(w io.Writer) (string, error) {
+		_, cmdStr, err := m.git.Fetch(git.FetchOptions{Remote: remote, All: remote == "", Progress: w})
+		return cmdStr, err
+	})
+}
+
+// promptPublishBranch asks for the remote to push a branch without an upstream
+// to, which becomes its upstream.
+func (m *Model) promptPublishBranch(branch string) tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = fmt.Sprintf("Publish Branch %s to Remote", branch)
+	m.textInput.SetValue(defaultRemote)
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg { return publishBranchMsg{branch: branch, remote: input} }
+	}
+	return nil
+}
+
+// handlePublishBranchMsg pushes the branch to the chosen remote and sets it as
+// the upstream of the branch.
+func (m Model) handlePublishBranchMsg(msg publishBranchMsg) (Model, tea.Cmd) {
+	title := fmt.Sprintf("git push --set-upstream %s %s", msg.remote, msg.branch)
+	cmd := m.startRemoteCommand(title, func(w io.Writer) (string, error) {
+		options := git.PushOptions{Remote: msg.remote, Branch: msg.branch, SetUpstream: true, Progress: w}
+		_, cmdStr, err := m.git.Push(options)
+		return cmdStr, err
+	})
+	return m, cmd
+}
+
+// startRemoteCommand runs a command that talks to a remote in the background,
+// showing its progress in the command log. Only one such command runs at a
+// time.
+func (m *Model) startRemoteCommand(title string, run func(w io.Writer) (string, error)) tea.Cmd {
+	if m.progress.running {
+		running := m.progress.title
+		return func() tea.Msg {
+			return errMsg{fmt.Errorf("%s is still running", running)}
+		}
+	}
+	lines, cmd := streamOutput(outputToLog, m.git.Context().Done(), run)
+	m.progress = progressState{running: true, title: title, stream: lines}
+	m.renderCommandLog()
+	return cmd
+}
+
+// commandLogContent returns the content of the command log: the progress of
+// the running remote command, if any, followed by the command history.
+func (m Model) commandLogContent() string {
+	content := strings.Join(m.CommandHistory, "\n\n")
+	if !m.progress.running {
+		return content
+	}
+	status := m.theme.HelpKey.Render(m.progress.title + " (running...)")
+	if m.progress.line != "" {
+		status += " " + m.progress.line
+	}
+	if content == "" {
+		return status
+	}
+	return status + "\n\n" + content
+}
+
+// renderCommandLog updates the command log to show the progress of the
+// running remote command.
+func (m *Model) renderCommandLog() {
+	content := m.commandLogContent()
+	m.panels[SecondaryPanel].content = content
+	m.panels[SecondaryPanel].viewport.SetContent(content)
+	m.panels[SecondaryPanel].viewport.GotoTop()
+}
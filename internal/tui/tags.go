@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// defaultRemote is suggested when a command needs the name of a remote.
+const defaultRemote = "origin"
+
+// tagsUpdatedMsg is sent when the tags have been fetched.
+type tagsUpdatedMsg struct {
+	tags []*git.Tag
+}
+
+// tagRemoteMsg is sent when the remote to push a tag to, or delete it from,
+// has been entered.
+type tagRemoteMsg struct {
+	options git.PushOptions
+}
+
+// tagMessageMsg is sent when the name of a new tag has been entered.
+type tagMessageMsg struct {
+	name   string
+	commit string
+}
+
+// handleTagsUpdatedMsg renders the tags into the Tags tab of the Branches panel.
+func (m Model) handleTagsUpdatedMsg(msg tagsUpdatedMsg) (Model, tea.Cmd) {
+	if m.panels[BranchesPanel].tab != tagsTab {
+		return m, nil
+	}
+
+	lines := make([]string, len(msg.tags))
+	for i, tag := range msg.tags {
+		kind := "lightweight"
+		if tag.Annotated {
+			kind = "annotated"
+		}
+		line := fmt.Sprintf("%s %s %s", m.theme.BranchCurrent.Render(tag.Name), m.theme.CommitSHA.Render(tag.Commit), m.theme.BranchDate.Render(kind))
+		if tag.Message != "" {
+			line += " " + tag.Message
+		}
+		lines[i] = line
+	}
+	if len(lines) == 0 {
+		lines = []string{"No tags."}
+	}
+
+	m.tags = msg.tags
+	content := strings.Join(lines, "\n")
+	m.panels[BranchesPanel].lines = lines
+	m.panels[BranchesPanel].content = content
+	m.panels[BranchesPanel].viewport.SetContent(content)
+	if m.panels[BranchesPanel].cursor >= len(lines) {
+		m.panels[BranchesPanel].cursor = len(lines) - 1
+	}
+	return m, m.updateMainPanel()
+}
+
+// selectedTag returns the tag under the cursor of the Tags tab, or nil if
+// there is none.
+func (m Model) selectedTag() *git.Tag {
+	cursor := m.panels[BranchesPanel].cursor
+	if cursor < 0 || cursor >= len(m.tags) {
+		return nil
+	}
+	return m.tags[cursor]
+}
+
+// handleTagsKeys handles the keybindings of the Tags tab of the Branches panel.
+func (m *Model) handleTagsKeys(msg tea.KeyMsg) tea.Cmd {
+	if handled, cmd := m.handleCursorMovement(msg); handled {
+		return cmd
+	}
+	if Matches(msg, m.keymap["new_tag"]) {
+		return m.promptNewTag("HEAD")
+	}
+
+	tag := m.selectedTag()
+	if tag == nil {
+		return nil
+	}
+	name := tag.Name
+
+	switch {
+	case Matches(msg, m.keymap["delete_tag"]):
+		m.mode = modeConfirm
+		m.confirmMessage = fmt.Sprintf("Delete tag %s?", name)
+		m.confirmCallback = func(confirmed bool) tea.Cmd {
+			m.mode = modeNormal
+			if !confirmed {
+				return nil
+			}
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.ManageTag(git.TagOptions{Delete: true, Name: name})
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+
+	case Matches(msg, m.keymap["push_tag"]):
+		return m.promptTagRemote(fmt.Sprintf("Push Tag %s to Remote", name), git.PushOptions{Tag: name})
+
+	case Matches(msg, m.keymap["delete_remote_tag"]):
+		return m.promptTagRemote(fmt.Sprintf("Delete Tag %s on Remote", name), git.PushOptions{Tag: name, Delete: true})
+	}
+	return nil
+}
+
+// promptNewTag asks for the name of a tag to create on the given commit, then
+// for its message.
+func (m *Model) promptNewTag(commit string) tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = fmt.Sprintf("New Tag on %s", commit)
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg { return tagMessageMsg{name: input, commit: commit} }
+	}
+	return nil
+}
+
+// handleTagMessageMsg asks for the message of the new tag and creates it. An
+// empty message creates a lightweight tag.
+func (m Model) handleTagMessageMsg(msg tagMessageMsg) (Model, tea.Cmd) {
+	m.mode = modeInput
+	m.promptTitle = "Tag Message (empty for a lightweight tag)"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	gc := m.git
+	m.inputCallback = func(input string) tea.Cmd {
+		return func() tea.Msg {
+			_, cmdStr, err := gc.ManageTag(git.TagOptions{Create: true, Name: msg.name, Message: input, Commit: msg.commit})
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return m, nil
+}
+
+// promptTagRemote asks for the remote to push a tag to, or delete it from.
+func (m *Model) promptTagRemote(title string, options git.PushOptions) tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = title
+	m.textInput.SetValue(defaultRemote)
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		m.mode = modeNormal
+		if input == "" {
+			return nil
+		}
+		options.Remote = input
+		return func() tea.Msg { return tagRemoteMsg{options: options} }
+	}
+	return nil
+}
+
+// handleTagRemoteMsg pushes the tag to the chosen remote, or deletes it there,
+// in the background like the other remote commands.
+func (m Model) handleTagRemoteMsg(msg tagRemoteMsg) (Model, tea.Cmd) {
+	options := msg.options
+	title := fmt.Sprintf("git push %s %s", options.Remote, options.Tag)
+	if options.Delete {
+		title = fmt.Sprintf("git push --delete %s %s", options.Remote, options.Tag)
+	}
+	cmd := m.startRemoteCommand(title, func(w io.Writer) (string, error) {
+		options.Progress = w
+		_, cmdStr, err := m.git.Push(options)
+		return cmdStr, err
+	})
+	return m, cmd
+}
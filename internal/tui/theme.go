@@ -117,11 +117,19 @@ type Theme struct {
 	GitUnstaged    lipgloss.Style
 	GitUntracked   lipgloss.Style
 	GitConflicted  lipgloss.Style
+	DiffAdded      lipgloss.Style
+	DiffRemoved    lipgloss.Style
+	DiffHunk       lipgloss.Style
 	BranchCurrent  lipgloss.Style
 	BranchDate     lipgloss.Style
+	BranchTracking lipgloss.Style
 	CommitSHA      lipgloss.Style
 	CommitAuthor   lipgloss.Style
 	CommitMerge    lipgloss.Style
+	CommitRefHead  lipgloss.Style
+	CommitBranch   lipgloss.Style
+	CommitRemote   lipgloss.Style
+	CommitTag      lipgloss.Style
 	GraphEdge      lipgloss.Style
 	GraphNode      lipgloss.Style
 	GraphColors    []lipgloss.Style
@@ -188,11 +196,19 @@ func NewThemeFromPalette(p Palette) Theme {
 		GitUnstaged:    lipgloss.NewStyle().Foreground(lipgloss.Color(p.Red)),
 		GitUntracked:   lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightBlack)),
 		GitConflicted:  lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightRed)).Bold(true),
+		DiffAdded:      lipgloss.NewStyle().Foreground(lipgloss.Color(p.Green)),
+		DiffRemoved:    lipgloss.NewStyle().Foreground(lipgloss.Color(p.Red)),
+		DiffHunk:       lipgloss.NewStyle().Foreground(lipgloss.Color(p.Cyan)),
 		BranchCurrent:  lipgloss.NewStyle().Foreground(lipgloss.Color(p.Green)).Bold(true),
 		BranchDate:     lipgloss.NewStyle().Foreground(lipgloss.Color(p.Yellow)),
+		BranchTracking: lipgloss.NewStyle().Foreground(lipgloss.Color(p.Cyan)),
 		CommitSHA:      lipgloss.NewStyle().Foreground(lipgloss.Color(p.Yellow)),
 		CommitAuthor:   lipgloss.NewStyle().Foreground(lipgloss.Color(p.Green)),
 		CommitMerge:    lipgloss.NewStyle().Foreground(lipgloss.Color(p.Magenta)),
+		CommitRefHead:  lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightCyan)).Bold(true),
+		CommitBranch:   lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightGreen)).Bold(true),
+		CommitRemote:   lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightRed)).Bold(true),
+		CommitTag:      lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightYellow)).Bold(true),
 		GraphEdge:      lipgloss.NewStyle().Foreground(lipgloss.Color(p.BrightBlack)),
 		GraphNode:      lipgloss.NewStyle().Foreground(lipgloss.Color(p.Green)),
 		GraphColors: []lipgloss.Style{
@@ -12,20 +12,25 @@ import (
 
 // App is the main application struct.
 type App struct {
-	program *tea.Program
+	program  *tea.Program
+	repoPath string
+	repos    chan string // The work trees to watch, sent when another repository is opened.
 }
 
-// NewApp initializes a new TUI application.
-func NewApp() *App {
-	m := initialModel()
-	return &App{
-		program: tea.NewProgram(
-			m,
-			tea.WithoutCatchPanics(),
-			tea.WithAltScreen(),
-			tea.WithMouseAllMotion(),
-		),
-	}
+// NewApp initializes a new TUI application for the repository at repoPath, or
+// the one in the current directory if repoPath is empty. Outside a repository,
+// it starts with the repository switcher.
+func NewApp(repoPath string) *App {
+	a := &App{repoPath: repoPath, repos: make(chan string, 1)}
+	m := initialModel(repoPath)
+	m.watchRepo = func(path string) { a.repos <- path }
+	a.program = tea.NewProgram(
+		m,
+		tea.WithoutCatchPanics(),
+		tea.WithAltScreen(),
+		tea.WithMouseAllMotion(),
+	)
+	return a
 }
 
 // Run starts the TUI application and the file watcher.
@@ -49,29 +54,7 @@ func (a *App) watchGitDir() {
 		}
 	}()
 
-	gc := git.NewGitCommands()
-	gitDir, err := gc.GetGitRepoPath()
-	if err != nil {
-		// Not in a git repo, no need to watch.
-		return
-	}
-
-	repoRoot := filepath.Dir(gitDir)
-
-	watchPaths := []string{
-		repoRoot,
-		gitDir,
-		filepath.Join(gitDir, "HEAD"),
-		filepath.Join(gitDir, "index"),
-		filepath.Join(gitDir, "refs"),
-	}
-
-	for _, path := range watchPaths {
-		if err := watcher.Add(path); err != nil {
-			// ignore errors for paths that might not exist yet
-			log.Printf("error watching path %s: %v", path, err.Error())
-		}
-	}
+	watched := watchRepo(watcher, a.repoPath, nil)
 
 	ticker := time.NewTicker(fileWatcherPollInterval)
 	defer ticker.Stop()
@@ -79,6 +62,9 @@ func (a *App) watchGitDir() {
 
 	for {
 		select {
+		case path := <-a.repos:
+			watched = watchRepo(watcher, path, watched)
+			needsUpdate = false
 		case _, ok := <-watcher.Events:
 			if !ok {
 				return
@@ -97,3 +83,45 @@ func (a *App) watchGitDir() {
 		}
 	}
 }
+
+// watchRepo stops watching the previously watched paths and watches the
+// work tree at repoPath and its .git directory instead. It returns the
+// watched paths.
+func watchRepo(watcher *fsnotify.Watcher, repoPath string, previous []string) []string {
+	for _, path := range previous {
+		_ = watcher.Remove(path)
+	}
+
+	gc := git.NewGitCommands().WithRepoPath(repoPath)
+	gitDir, err := gc.GetGitRepoPath()
+	if err != nil {
+		// Not in a git repo, no need to watch.
+		return nil
+	}
+
+	// The .git directory of a submodule or a linked worktree is not inside
+	// its work tree, so the root is asked for separately.
+	repoRoot, err := gc.GetRepoRoot()
+	if err != nil {
+		repoRoot = filepath.Dir(gitDir)
+	}
+
+	watchPaths := []string{
+		repoRoot,
+		gitDir,
+		filepath.Join(gitDir, "HEAD"),
+		filepath.Join(gitDir, "index"),
+		filepath.Join(gitDir, "refs"),
+	}
+
+	var watched []string
+	for _, path := range watchPaths {
+		if err := watcher.Add(path); err != nil {
+			// ignore errors for paths that might not exist yet
+			log.Printf("error watching path %s: %v", path, err.Error())
+			continue
+		}
+		watched = append(watched, path)
+	}
+	return watched
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -19,9 +20,15 @@ type commandExecutedMsg struct {
 // panelContentUpdatedMsg is sent when new content for a panel has been fetched.
 type panelContentUpdatedMsg struct {
 	panel   Panel
+	tab     panelTab // The tab the content was fetched for.
 	content string
 }
 
+// fileStatusUpdatedMsg is sent when the status of the working tree has been fetched.
+type fileStatusUpdatedMsg struct {
+	status *git.RepoStatus
+}
+
 // mainContentUpdatedMsg is sent when the content for the main panel has been fetched.
 type mainContentUpdatedMsg struct {
 	content string
@@ -44,13 +51,10 @@ func (e errMsg) Error() string { return e.err.Error() }
 // Update is the main message handler for the TUI. It processes user input,
 // window events, and application-specific messages.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch m.mode {
-	case modeInput:
-		return m.updateInput(msg)
-	case modeConfirm:
-		return m.updateConfirm(msg)
-	case modeCommit:
-		return m.updateCommit(msg)
+	// Data from background commands is handled while a pop-up is shown as
+	// well, so the pop-up never stalls them, e.g. a command streaming output.
+	if m.mode != modeNormal && !isDataMsg(msg) {
+		return m.updatePopup(msg)
 	}
 
 	var cmd tea.Cmd
@@ -63,7 +67,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// A command was successful, add it to our history.
 		m.CommandHistory = append([]string{msg.cmdStr}, m.CommandHistory...)
 		// Update the history viewport content.
-		historyContent := strings.Join(m.CommandHistory, "\n\n")
+		historyContent := m.commandLogContent()
 		m.panels[SecondaryPanel].content = historyContent
 		m.panels[SecondaryPanel].viewport.SetContent(historyContent)
 		m.panels[SecondaryPanel].viewport.GotoTop()
@@ -82,10 +86,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		log.Printf("error: %v", msg)
 
 		errorLine := m.theme.ErrorText.Render(msg.Error())
+		if hint := errorHint(msg.err); hint != "" {
+			errorLine += "\n" + hint
+		}
 		m.CommandHistory = append([]string{errorLine}, m.CommandHistory...)
 
 		// Update the history panel's content and scroll to the new error.
-		rawHistoryContent := strings.Join(m.CommandHistory, "\n\n")
+		rawHistoryContent := m.commandLogContent()
 		contentWidth := m.panels[SecondaryPanel].viewport.Width
 		wrappedContent := lipgloss.NewStyle().Width(contentWidth).Render(rawHistoryContent)
 
@@ -99,57 +106,158 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.fetchPanelContent(StatusPanel),
 		)
 
+	case stagingDiffMsg:
+		return m.handleStagingDiffMsg(msg)
+
+	case outputLineMsg:
+		return m.handleOutputLineMsg(msg)
+
+	case outputDoneMsg:
+		return m.handleOutputDoneMsg(msg)
+
+	case bisectRunMsg:
+		return m.handleBisectRunMsg(msg)
+
+	case undoPlannedMsg:
+		return m.handleUndoPlannedMsg(msg)
+
+	case remotesUpdatedMsg:
+		return m.handleRemotesUpdatedMsg(msg)
+
+	case remoteAddURLMsg:
+		return m.handleRemoteAddURLMsg(msg)
+
+	case worktreesUpdatedMsg:
+		return m.handleWorktreesUpdatedMsg(msg)
+
+	case worktreePathMsg:
+		return m.handleWorktreePathMsg(msg)
+
+	case submodulesUpdatedMsg:
+		return m.handleSubmodulesUpdatedMsg(msg)
+
+	case tagsUpdatedMsg:
+		return m.handleTagsUpdatedMsg(msg)
+
+	case publishBranchMsg:
+		return m.handlePublishBranchMsg(msg)
+
+	case tagMessageMsg:
+		return m.handleTagMessageMsg(msg)
+
+	case tagRemoteMsg:
+		return m.handleTagRemoteMsg(msg)
+
+	case conflictFileMsg:
+		return m.handleConflictFileMsg(msg)
+
+	case conflictResolvedMsg:
+		return m.handleConflictResolvedMsg(msg)
+
+	case commitLogUpdatedMsg:
+		return m.handleCommitLogUpdatedMsg(msg)
+
+	case fileHistoryRequestedMsg:
+		return m, m.showFileHistory(msg.path)
+
+	case repoPathRequestedMsg:
+		return m, openRepo(msg.path)
+
+	case repoOpenedMsg:
+		return m.handleRepoOpenedMsg(msg)
+
+	case fileHistoryUpdatedMsg:
+		return m.handleFileHistoryUpdatedMsg(msg)
+
+	case blameLoadedMsg:
+		return m.handleBlameLoadedMsg(msg)
+
+	case rebaseTodoLoadedMsg:
+		return m.handleRebaseTodoLoadedMsg(msg)
+
+	case rebaseRewordMsg:
+		return m.handleRebaseRewordMsg(msg)
+
+	case statusUpdatedMsg:
+		return m.handleStatusUpdatedMsg(msg)
+
 	case mainContentUpdatedMsg:
+		if m.mainView != mainViewDiff {
+			return m, nil // The Main panel is showing a dedicated view.
+		}
 		m.panels[MainPanel].content = msg.content
 		m.panels[MainPanel].viewport.SetContent(msg.content)
 		return m, nil
 
-	case panelContentUpdatedMsg:
+	case fileStatusUpdatedMsg:
+		if m.panels[FilesPanel].tab != defaultTab {
+			return m, nil // The Files panel is showing another tab.
+		}
+		// Remember the path of the currently selected item to preserve the
+		// cursor position after the refresh.
 		var selectedPath string
-		// If the FilesPanel is being updated, try to find the path of the
-		// currently selected item to preserve the cursor position after the refresh.
-		if msg.panel == FilesPanel && m.panels[FilesPanel].cursor < len(m.panels[FilesPanel].lines) {
-			line := m.panels[FilesPanel].lines[m.panels[FilesPanel].cursor]
-			parts := strings.Split(line, "\t")
-			if len(parts) == 4 {
-				selectedPath = parts[3]
+		if node := m.selectedFileNode(); node != nil {
+			selectedPath = node.path
+		}
+
+		root := BuildTree(msg.status.Files)
+		renderedTree := root.Render(m.theme)
+		m.fileNodes = root.Nodes()
+		m.panels[FilesPanel].lines = renderedTree
+		m.panels[FilesPanel].viewport.SetContent(strings.Join(renderedTree, "\n"))
+
+		// Restore the cursor to the previously selected file path.
+		newCursorPos := 0 // Default to top.
+		if selectedPath != "" {
+			for i, node := range m.fileNodes {
+				if node.path == selectedPath {
+					newCursorPos = i
+					break
+				}
 			}
 		}
+		m.panels[FilesPanel].cursor = newCursorPos
+		return m, m.updateMainPanel()
 
+	case panelContentUpdatedMsg:
+		if msg.panel == CommitsPanel && m.rebaseTodo.active {
+			return m, nil // The Commits panel is showing the rebase editor.
+		}
+		if msg.tab != m.panels[msg.panel].tab {
+			return m, nil // The content is for a tab that is no longer shown.
+		}
+		if msg.panel == CommitsPanel && msg.tab == defaultTab && m.fileHistory.path != "" {
+			return m, nil // The Commits panel is showing the history of a file.
+		}
 		oldCursor := m.panels[msg.panel].cursor
-
 		if msg.panel == FilesPanel {
-			root := BuildTree(msg.content)
-			renderedTree := root.Render(m.theme)
-			m.panels[FilesPanel].lines = renderedTree
-			m.panels[FilesPanel].viewport.SetContent(strings.Join(renderedTree, "\n"))
-
-			// Restore the cursor to the previously selected file path.
-			newCursorPos := 0 // Default to top.
-			if selectedPath != "" {
-				for i, line := range renderedTree {
-					parts := strings.Split(line, "\t")
-					if len(parts) == 4 && parts[3] == selectedPath {
-						newCursorPos = i
-						break
-					}
-				}
-			}
-			m.panels[FilesPanel].cursor = newCursorPos
+			// The content is an error message, not a file tree.
+			m.fileNodes = nil
+			m.worktrees = nil
+			m.submodules = nil
+		}
+		if msg.panel == CommitsPanel {
+			// The content is an error message, not the commit log.
+			m.commitLog = commitLogState{limit: m.commitLog.limit}
+		}
+		if msg.panel == BranchesPanel {
+			// The content is an error message or the local branches.
+			m.remotes.items = nil
+			m.tags = nil
+		}
+
+		lines := strings.Split(msg.content, "\n")
+		m.panels[msg.panel].lines = lines
+		m.panels[msg.panel].viewport.SetContent(msg.content)
+		m.panels[msg.panel].content = msg.content
+
+		// Restore cursor by index for other, more stable panels.
+		if oldCursor < len(lines) {
+			m.panels[msg.panel].cursor = oldCursor
+		} else if len(lines) > 0 {
+			m.panels[msg.panel].cursor = len(lines) - 1
 		} else {
-			lines := strings.Split(msg.content, "\n")
-			m.panels[msg.panel].lines = lines
-			m.panels[msg.panel].viewport.SetContent(msg.content)
-			m.panels[msg.panel].content = msg.content
-
-			// Restore cursor by index for other, more stable panels.
-			if oldCursor < len(lines) {
-				m.panels[msg.panel].cursor = oldCursor
-			} else if len(lines) > 0 {
-				m.panels[msg.panel].cursor = len(lines) - 1
-			} else {
-				m.panels[msg.panel].cursor = 0
-			}
+			m.panels[msg.panel].cursor = 0
 		}
 		return m, m.updateMainPanel()
 
@@ -189,6 +297,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 	case tea.KeyMsg:
+		if m.repoRoot == "" {
+			// Without a repository, only the repository switcher can be used.
+			m.showRepoSwitcher(nil)
+			return m, nil
+		}
 		if m.showHelp {
 			switch {
 			case Matches(msg, m.keymap["toggle_help"]):
@@ -209,7 +322,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case Matches(msg, m.keymap["escape"]):
-			return m, nil
+			return m, m.escape()
 
 		case Matches(msg, m.keymap["toggle_help"]):
 			m.toggleHelp()
@@ -217,6 +330,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case Matches(msg, m.keymap["switch_theme"]):
 			m.nextTheme()
 
+		case Matches(msg, m.keymap["switch_repo"]):
+			m.showRepoSwitcher(nil)
+			return m, nil
+
+		case Matches(msg, m.keymap["cancel"]):
+			return m, m.cancelOperations()
+
+		case Matches(msg, m.keymap["undo"]) && !m.keysCaptured():
+			return m, m.planUndo(false)
+
+		case Matches(msg, m.keymap["redo"]) && !m.keysCaptured():
+			return m, m.planUndo(true)
+
 		case Matches(msg, m.keymap["focus_next"]), Matches(msg, m.keymap["focus_prev"]),
 			Matches(msg, m.keymap["focus_main"]), Matches(msg, m.keymap["focus_status"]),
 			Matches(msg, m.keymap["focus_files"]), Matches(msg, m.keymap["focus_branches"]),
@@ -226,8 +352,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		cmd = m.handlePanelKeys(msg)
-		if cmd != nil {
+		if cmd != nil || m.keysCaptured() {
 			cmds = append(cmds, cmd)
+			if m.focusedPanel != oldFocus {
+				m = m.recalculateLayout()
+			}
 			return m, tea.Batch(cmds...)
 		}
 	}
@@ -240,6 +369,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Update the active source panel and main panel content if the new focus is a source panel
 		if m.focusedPanel != MainPanel && m.focusedPanel != SecondaryPanel {
+			m.mainView = mainViewDiff
 			m.activeSourcePanel = m.focusedPanel
 			m.panels[MainPanel].viewport.GotoTop() // Reset main panel scroll on source change
 			cmd = m.updateMainPanel()
@@ -256,6 +386,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// isDataMsg reports whether msg carries data fetched or written by a
+// background command, which updates the panels without acting on its own.
+func isDataMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case commandExecutedMsg, errMsg, outputLineMsg, outputDoneMsg,
+		remotesUpdatedMsg, worktreesUpdatedMsg, submodulesUpdatedMsg, tagsUpdatedMsg,
+		commitLogUpdatedMsg, fileHistoryUpdatedMsg, statusUpdatedMsg,
+		mainContentUpdatedMsg, fileStatusUpdatedMsg, panelContentUpdatedMsg, fileWatcherMsg:
+		return true
+	}
+	return false
+}
+
+// isActionMsg reports whether msg opens a pop-up, switches the view of a panel
+// or acts on the repository, e.g. once the input of a prompt has been entered.
+func isActionMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case stagingDiffMsg, bisectRunMsg, undoPlannedMsg, remoteAddURLMsg,
+		worktreePathMsg, publishBranchMsg, tagMessageMsg, tagRemoteMsg, conflictFileMsg,
+		conflictResolvedMsg, fileHistoryRequestedMsg, repoPathRequestedMsg,
+		repoOpenedMsg, blameLoadedMsg, rebaseTodoLoadedMsg, rebaseRewordMsg:
+		return true
+	}
+	return false
+}
+
+// updatePopup handles updates while a pop-up is shown. Messages that would
+// open another pop-up or act behind the one shown are held until it is closed.
+func (m Model) updatePopup(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(repoOpenedMsg); ok && m.mode == modeRepoSwitcher {
+		return m.handleRepoOpenedMsg(msg) // The repository chosen in the switcher.
+	}
+	if isActionMsg(msg) {
+		m.heldMsgs = append(m.heldMsgs, msg)
+		return m, nil
+	}
+
+	var updated tea.Model
+	var cmd tea.Cmd
+	switch m.mode {
+	case modeInput:
+		updated, cmd = m.updateInput(msg)
+	case modeConfirm:
+		updated, cmd = m.updateConfirm(msg)
+	case modeCommit:
+		updated, cmd = m.updateCommit(msg)
+	case modeRepoSwitcher:
+		updated, cmd = m.updateRepoSwitcher(msg)
+	default:
+		return m, nil
+	}
+
+	next := updated.(Model)
+	if next.mode != modeNormal || len(next.heldMsgs) == 0 {
+		return next, cmd
+	}
+	// The held messages are sent again in order; any of them that opens a
+	// pop-up holds back the rest once more.
+	var held []tea.Cmd
+	for _, msg := range next.heldMsgs {
+		held = append(held, func() tea.Msg { return msg })
+	}
+	next.heldMsgs = nil
+	return next, tea.Batch(cmd, tea.Sequence(held...))
+}
+
 // updateInput handles updates when in text input mode.
 func (m Model) updateInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -345,48 +541,88 @@ func (m Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 // fetchPanelContent returns a command that fetches the content for a specific panel.
 func (m Model) fetchPanelContent(panel Panel) tea.Cmd {
 	return func() tea.Msg {
-		var content, repoName, branchName string
+		var content, repoName string
 		var err error
 		switch panel {
 		case StatusPanel:
-			repoName, branchName, err = m.git.GetRepoInfo()
+			repoName, _, err = m.git.GetRepoInfo()
 			if err == nil {
-				repo := m.theme.BranchCurrent.Render(repoName)
-				branch := m.theme.BranchCurrent.Render(branchName)
-				content = fmt.Sprintf("%s → %s", repo, branch)
+				var state *git.RepoState
+				state, err = m.git.GetRepoState()
+				if err == nil {
+					return statusUpdatedMsg{content: m.statusContent(repoName, state), state: *state}
+				}
 			}
 		case FilesPanel:
-			content, err = m.git.GetStatus(git.StatusOptions{Porcelain: true})
-		case BranchesPanel:
-			var branchList []*git.Branch
-			branchList, err = m.git.GetBranches()
+			if m.panels[FilesPanel].tab == worktreesTab {
+				var worktrees []*git.Worktree
+				worktrees, err = m.git.GetWorktrees()
+				if err == nil {
+					return worktreesUpdatedMsg{worktrees: worktrees}
+				}
+				break
+			}
+			if m.panels[FilesPanel].tab == submodulesTab {
+				var submodules []*git.Submodule
+				submodules, err = m.git.GetSubmodules()
+				if err == nil {
+					return submodulesUpdatedMsg{submodules: submodules}
+				}
+				break
+			}
+			var status *git.RepoStatus
+			status, err = m.git.GetRepoStatus()
 			if err == nil {
-				var builder strings.Builder
-				for _, b := range branchList {
-					name := b.Name
-					if b.IsCurrent {
-						name = fmt.Sprintf("(*) → %s", b.Name)
+				return fileStatusUpdatedMsg{status: status}
+			}
+		case BranchesPanel:
+			switch m.panels[BranchesPanel].tab {
+			case remotesTab:
+				var remotes []*git.Remote
+				remotes, err = m.fetchRemotes()
+				if err == nil {
+					return remotesUpdatedMsg{remotes: remotes}
+				}
+			case tagsTab:
+				var tags []*git.Tag
+				tags, err = m.git.GetTags()
+				if err == nil {
+					return tagsUpdatedMsg{tags: tags}
+				}
+			default:
+				var branchList []*git.Branch
+				branchList, err = m.git.GetBranches()
+				if err == nil {
+					var builder strings.Builder
+					for _, b := range branchList {
+						name := b.Name
+						if b.IsCurrent {
+							name = fmt.Sprintf("(*) → %s", b.Name)
+						}
+						info := strings.TrimSpace(formatTracking(b.Tracking) + " " + formatBranchWorktree(b))
+						line := fmt.Sprintf("%s\t%s\t%s", b.LastCommit, name, info)
+						builder.WriteString(line + "\n")
 					}
-					line := fmt.Sprintf("%s\t%s", b.LastCommit, name)
-					builder.WriteString(line + "\n")
+					content = strings.TrimSpace(builder.String())
 				}
-				content = strings.TrimSpace(builder.String())
 			}
 		case CommitsPanel:
+			if m.panels[CommitsPanel].tab == reflogTab {
+				content, err = m.reflogContent()
+				break
+			}
+			if path := m.fileHistory.path; path != "" {
+				logs, err := m.git.GetFileHistory(path)
+				if isCancelled(err) {
+					return nil
+				}
+				return fileHistoryUpdatedMsg{path: path, logs: logs, err: err}
+			}
+			limit := m.commitLog.limit
 			var logs []git.CommitLog
-			logs, err = m.git.GetCommitLogsGraph()
+			logs, err = m.git.GetCommitLogsGraph(0, limit)
 			if err == nil {
-				var builder strings.Builder
-				for _, log := range logs {
-					var line string
-					if log.SHA != "" {
-						line = fmt.Sprintf("%s\t%s\t%s\t%s", log.Graph, log.SHA, log.AuthorInitials, log.Subject)
-					} else {
-						line = log.Graph
-					}
-					builder.WriteString(line + "\n")
-				}
-				content = strings.TrimSpace(builder.String())
+				return commitLogUpdatedMsg{logs: logs, limit: limit}
 			}
 		case StashPanel:
 			var stashList []*git.Stash
@@ -412,17 +648,47 @@ func (m Model) fetchPanelContent(panel Panel) tea.Cmd {
 			err = nil // Set err to nil as there's no operation that can fail here.
 		}
 
+		if isCancelled(err) {
+			return nil
+		}
 		if err != nil {
 			content = "Error: " + err.Error()
 		}
-		return panelContentUpdatedMsg{panel: panel, content: content}
+		return panelContentUpdatedMsg{panel: panel, tab: m.panels[panel].tab, content: content}
 	}
 }
 
 // updateMainPanel returns a command that fetches the content for the main panel
 // based on the currently active source panel.
 func (m *Model) updateMainPanel() tea.Cmd {
+	switch m.mainView {
+	case mainViewStaging:
+		return m.loadStagingDiff()
+	case mainViewConflicts:
+		return nil // Reloading the file would discard the chosen resolutions.
+	case mainViewOutput:
+		return nil // The output is streamed by the running command.
+	case mainViewBlame:
+		return m.loadBlame()
+	}
+
+	// Only the content for the latest selection is of interest, so a fetch
+	// still running for a previous one is cancelled.
+	if m.cancelMainFetch != nil {
+		m.cancelMainFetch()
+	}
+	ctx, cancel := context.WithCancel(m.git.Context())
+	m.cancelMainFetch = cancel
+	fetch := *m
+	fetch.git = m.git.WithContext(ctx)
+	return fetch.fetchMainContent(cancel)
+}
+
+// fetchMainContent returns a command that fetches the content for the main
+// panel with the git commands of m, and calls done once it is fetched.
+func (m Model) fetchMainContent(done context.CancelFunc) tea.Cmd {
 	return func() tea.Msg {
+		defer done()
 		var content string
 		var err error
 		switch m.activeSourcePanel {
@@ -433,34 +699,30 @@ func (m *Model) updateMainPanel() tea.Cmd {
 			msgBody := fmt.Sprintf(welcomeMsg, m.theme.UserName.Render(userName), url)
 			content = fmt.Sprintf(msgHeading, m.theme.WelcomeMsg.Render(msgBody))
 		case FilesPanel:
-			if m.panels[FilesPanel].cursor < len(m.panels[FilesPanel].lines) {
-				line := m.panels[FilesPanel].lines[m.panels[FilesPanel].cursor]
-				parts := strings.Split(line, "\t")
-
-				if len(parts) == 4 {
-					status := parts[1]
-					path := parts[3] // Always use the full path from the 4th column
-
-					if path != "" {
-						if status == "" { // It's a directory
-							content, err = m.git.ShowDiff(git.DiffOptions{Color: true, Commit1: "HEAD", Commit2: path})
-						} else { // It's a file
-							stagedChanges := status[0] != ' ' && status[0] != '?'
-							unstagedChanges := status[1] != ' '
-
-							if stagedChanges {
-								content, err = m.git.ShowDiff(git.DiffOptions{Color: true, Cached: true, Commit1: path})
-							} else if unstagedChanges {
-								content, err = m.git.ShowDiff(git.DiffOptions{Color: true, Commit1: path})
-							} else if status == "??" {
-								content = "Untracked file: Stage to see content as a diff."
-							}
-						}
-					}
+			if m.panels[FilesPanel].tab == worktreesTab {
+				content, err = m.worktreeDetails()
+			} else if m.panels[FilesPanel].tab == submodulesTab {
+				content, err = m.submoduleDetails()
+			} else if node := m.selectedFileNode(); node != nil {
+				path := node.path
+				if node.file == nil { // It's a directory
+					content, err = m.git.ShowDiff(git.DiffOptions{Color: true, Commit1: "HEAD", Commit2: path})
+				} else if node.file.IsUntracked() {
+					content = "Untracked file: Stage to see content as a diff."
+				} else if node.file.HasStagedChanges() {
+					content, err = m.git.ShowDiff(git.DiffOptions{Color: true, Cached: true, Commit1: path, Submodule: "log"})
+				} else if node.file.HasUnstagedChanges() {
+					content, err = m.git.ShowDiff(git.DiffOptions{Color: true, Commit1: path, Submodule: "log"})
 				}
 			}
 		case BranchesPanel:
-			if m.panels[BranchesPanel].cursor < len(m.panels[BranchesPanel].lines) {
+			if m.panels[BranchesPanel].tab == remotesTab {
+				content, err = m.remoteDetails()
+			} else if m.panels[BranchesPanel].tab == tagsTab {
+				if tag := m.selectedTag(); tag != nil {
+					content, err = m.git.ShowCommit(tag.Name)
+				}
+			} else if m.panels[BranchesPanel].cursor < len(m.panels[BranchesPanel].lines) {
 				line := m.panels[BranchesPanel].lines[m.panels[BranchesPanel].cursor]
 				parts := strings.Split(line, "\t")
 				if len(parts) > 1 {
@@ -469,12 +731,16 @@ func (m *Model) updateMainPanel() tea.Cmd {
 				}
 			}
 		case CommitsPanel:
-			if m.panels[CommitsPanel].cursor < len(m.panels[CommitsPanel].lines) {
+			if m.rebaseTodo.active {
+				if sha := m.selectedRebaseTodoSHA(); sha != "" {
+					content, err = m.git.ShowCommit(sha)
+				}
+			} else if m.panels[CommitsPanel].cursor < len(m.panels[CommitsPanel].lines) {
 				line := m.panels[CommitsPanel].lines[m.panels[CommitsPanel].cursor]
 				parts := strings.Split(line, "\t")
 				if len(parts) >= 2 {
 					sha := parts[1]
-					content, err = m.git.ShowCommit(sha)
+					content, err = m.showCommit(sha)
 				}
 			}
 		case StashPanel:
@@ -490,6 +756,9 @@ func (m *Model) updateMainPanel() tea.Cmd {
 			}
 		}
 
+		if isCancelled(err) {
+			return nil
+		}
 		if err != nil {
 			content = "Error: " + err.Error()
 		}
@@ -574,7 +843,32 @@ func (m Model) handleMouseMsg(msg tea.MouseMsg) (Model, tea.Cmd) {
 
 // handlePanelKeys handles keybindings that are specific to the focused panel.
 func (m *Model) handlePanelKeys(msg tea.KeyMsg) tea.Cmd {
+	if !m.keysCaptured() {
+		switch {
+		case Matches(msg, m.keymap["next_tab"]):
+			return m.switchTab(1)
+		case Matches(msg, m.keymap["prev_tab"]):
+			return m.switchTab(-1)
+		}
+		if !m.panelBindsSyncKeys() {
+			if cmd := m.handleSyncKeys(msg); cmd != nil {
+				return cmd
+			}
+		}
+	}
+
 	switch m.focusedPanel {
+	case StatusPanel:
+		return m.handleOperationKeys(msg)
+	case MainPanel:
+		switch m.mainView {
+		case mainViewStaging:
+			return m.handleStagingKeys(msg)
+		case mainViewConflicts:
+			return m.handleConflictKeys(msg)
+		case mainViewBlame:
+			return m.handleBlameKeys(msg)
+		}
 	case FilesPanel:
 		return m.handleFilesPanelKeys(msg)
 	case BranchesPanel:
@@ -587,6 +881,36 @@ func (m *Model) handlePanelKeys(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+// keysCaptured reports whether the focused panel is showing a dedicated view
+// that consumes all keys, so they must not reach the panel's viewport.
+func (m Model) keysCaptured() bool {
+	switch m.focusedPanel {
+	case MainPanel:
+		// The output view is scrolled like the default view.
+		return m.mainView != mainViewDiff && m.mainView != mainViewOutput
+	case CommitsPanel:
+		return m.rebaseTodo.active
+	}
+	return false
+}
+
+// escape leaves the dedicated view of the focused panel, if any.
+func (m *Model) escape() tea.Cmd {
+	switch {
+	case m.focusedPanel == CommitsPanel && m.rebaseTodo.active:
+		return m.cancelRebaseTodo()
+	case m.focusedPanel == CommitsPanel && len(m.copiedCommits) > 0:
+		m.copiedCommits = nil
+	case m.focusedPanel == CommitsPanel && m.fileHistory.path != "":
+		return m.clearFileHistory()
+	case m.mainView != mainViewDiff:
+		return m.escapeMainView()
+	case len(m.parentRepos) > 0:
+		return m.returnToParentRepo()
+	}
+	return nil
+}
+
 // handleCursorMovement is a helper to handle up/down cursor movement in selectable panels.
 // It returns true if the key was handled.
 func (m *Model) handleCursorMovement(msg tea.KeyMsg) (bool, tea.Cmd) {
@@ -617,21 +941,32 @@ func (m *Model) handleCursorMovement(msg tea.KeyMsg) (bool, tea.Cmd) {
 	return false, nil
 }
 
+// selectedFileNode returns the file tree node under the cursor of the Files
+// panel, or nil if there is none.
+func (m Model) selectedFileNode() *Node {
+	cursor := m.panels[FilesPanel].cursor
+	if m.panels[FilesPanel].tab != defaultTab || cursor < 0 || cursor >= len(m.fileNodes) {
+		return nil
+	}
+	return m.fileNodes[cursor]
+}
+
 func (m *Model) handleFilesPanelKeys(msg tea.KeyMsg) tea.Cmd {
+	switch m.panels[FilesPanel].tab {
+	case worktreesTab:
+		return m.handleWorktreesKeys(msg)
+	case submodulesTab:
+		return m.handleSubmodulesKeys(msg)
+	}
 	if handled, cmd := m.handleCursorMovement(msg); handled {
 		return cmd
 	}
 
-	if m.panels[FilesPanel].cursor >= len(m.panels[FilesPanel].lines) {
-		return nil
-	}
-	line := m.panels[FilesPanel].lines[m.panels[FilesPanel].cursor]
-	parts := strings.Split(line, "\t")
-	if len(parts) < 4 {
+	node := m.selectedFileNode()
+	if node == nil {
 		return nil
 	}
-	status := parts[1]
-	filePath := parts[3]
+	filePath := node.path
 
 	switch {
 	case Matches(msg, m.keymap["commit"]):
@@ -654,10 +989,16 @@ func (m *Model) handleFilesPanelKeys(msg tea.KeyMsg) tea.Cmd {
 		}
 
 	case Matches(msg, m.keymap["stage_item"]):
+		// Files without staged changes are staged, others are unstaged. A
+		// directory is staged as long as anything below it is unstaged.
+		stage := node.hasUnstagedChanges()
+		if node.file != nil {
+			stage = !node.file.HasStagedChanges()
+		}
 		return func() tea.Msg {
 			var cmdStr string
 			var err error
-			if status[0] == ' ' || status[0] == '?' {
+			if stage {
 				_, cmdStr, err = m.git.AddFiles([]string{filePath})
 			} else {
 				_, cmdStr, err = m.git.ResetFiles([]string{filePath})
@@ -668,6 +1009,37 @@ func (m *Model) handleFilesPanelKeys(msg tea.KeyMsg) tea.Cmd {
 			return commandExecutedMsg{cmdStr}
 		}
 
+	case Matches(msg, m.keymap["stage_hunks"]):
+		if node.file == nil {
+			return nil
+		}
+		if node.file.Submodule.IsSubmodule {
+			return m.openSubmodule(filePath)
+		}
+		if node.file.IsConflicted() {
+			return m.enterConflictView(*node.file)
+		}
+		return m.enterStagingView(*node.file)
+
+	case Matches(msg, m.keymap["checkout_ours"]), Matches(msg, m.keymap["checkout_theirs"]):
+		if node.file == nil || !node.file.IsConflicted() {
+			return nil
+		}
+		side := git.SideOurs
+		if Matches(msg, m.keymap["checkout_theirs"]) {
+			side = git.SideTheirs
+		}
+		return m.confirmCheckoutConflictSide(filePath, side)
+
+	case Matches(msg, m.keymap["file_history"]):
+		return m.showFileHistory(filePath)
+
+	case Matches(msg, m.keymap["blame"]):
+		if node.file == nil || node.file.IsUntracked() || node.file.Submodule.IsSubmodule {
+			return nil
+		}
+		return m.enterBlameView(filePath)
+
 	case Matches(msg, m.keymap["stage_all"]):
 		return func() tea.Msg {
 			_, cmdStr, err := m.git.AddFiles([]string{"."})
@@ -710,6 +1082,12 @@ func (m *Model) handleFilesPanelKeys(msg tea.KeyMsg) tea.Cmd {
 }
 
 func (m *Model) handleBranchesPanelKeys(msg tea.KeyMsg) tea.Cmd {
+	switch m.panels[BranchesPanel].tab {
+	case remotesTab:
+		return m.handleRemotesKeys(msg)
+	case tagsTab:
+		return m.handleTagsKeys(msg)
+	}
 	if handled, cmd := m.handleCursorMovement(msg); handled {
 		return cmd
 	}
@@ -797,13 +1175,58 @@ func (m *Model) handleBranchesPanelKeys(msg tea.KeyMsg) tea.Cmd {
 				return commandExecutedMsg{cmdStr}
 			}
 		}
+
+	case Matches(msg, m.keymap["set_upstream"]):
+		m.mode = modeInput
+		m.promptTitle = fmt.Sprintf("Upstream of %s", branchName)
+		m.textInput.SetValue(fmt.Sprintf("%s/%s", defaultRemote, branchName))
+		m.textInput.Focus()
+		m.inputCallback = func(input string) tea.Cmd {
+			m.mode = modeNormal
+			if input == "" {
+				return nil
+			}
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.SetUpstream(branchName, input)
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+
+	case Matches(msg, m.keymap["unset_upstream"]):
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.UnsetUpstream(branchName)
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
 	}
 	return nil
 }
 
 func (m *Model) handleCommitsPanelKeys(msg tea.KeyMsg) tea.Cmd {
+	if m.rebaseTodo.active {
+		return m.handleRebaseTodoKeys(msg)
+	}
+	if m.panels[CommitsPanel].tab == reflogTab {
+		return m.handleReflogKeys(msg)
+	}
 	if handled, cmd := m.handleCursorMovement(msg); handled {
-		return cmd
+		return tea.Batch(cmd, m.loadMoreCommits())
+	}
+	if op := m.repoState.Operation; op == git.OperationRebase || op == git.OperationCherryPick || op == git.OperationBisect {
+		if cmd := m.handleOperationKeys(msg); cmd != nil || m.mode != modeNormal {
+			return cmd
+		}
+	}
+	if Matches(msg, m.keymap["paste_commits"]) {
+		return m.pasteCopiedCommits()
+	}
+	if Matches(msg, m.keymap["file_history"]) {
+		return m.promptFileHistory()
 	}
 
 	if m.panels[CommitsPanel].cursor >= len(m.panels[CommitsPanel].lines) {
@@ -817,6 +1240,24 @@ func (m *Model) handleCommitsPanelKeys(msg tea.KeyMsg) tea.Cmd {
 	sha := parts[1]
 
 	switch {
+	case Matches(msg, m.keymap["interactive_rebase"]):
+		return m.startRebaseTodo(sha)
+
+	case Matches(msg, m.keymap["copy_commit"]):
+		m.toggleCopiedCommit(sha)
+
+	case Matches(msg, m.keymap["new_tag"]):
+		return m.promptNewTag(sha)
+
+	case Matches(msg, m.keymap["bisect_bad"]):
+		return m.bisectMark(git.BisectBad, sha)
+
+	case Matches(msg, m.keymap["bisect_good"]):
+		return m.bisectMark(git.BisectGood, sha)
+
+	case Matches(msg, m.keymap["bisect_run"]):
+		return m.promptBisectRun()
+
 	case Matches(msg, m.keymap["amend_commit"]):
 		m.mode = modeCommit
 		m.textInput.SetValue("")
@@ -976,6 +1417,9 @@ func (m Model) recalculateLayout() Model {
 
 	// Left Column Layout
 	m.panelHeights[StatusPanel] = statusPanelHeight
+	if m.hasOperationBanner() {
+		m.panelHeights[StatusPanel] += statusBannerHeight
+	}
 	remainingHeight := contentHeight - m.panelHeights[StatusPanel]
 
 	if m.focusedPanel == StashPanel {
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gitxtui/gitx/internal/git"
 	zone "github.com/lrstanley/bubblezone"
 )
 
@@ -39,6 +40,8 @@ func (m Model) View() string {
 			popup = m.renderConfirmPopup()
 		case modeCommit:
 			popup = m.renderCommitPopup()
+		case modeRepoSwitcher:
+			popup = m.renderRepoSwitcherPopup()
 		}
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
 	}
@@ -110,6 +113,22 @@ func (m Model) renderMainView() string {
 		MainPanel: panelZero, StatusPanel: panelOne, FilesPanel: panelTwo,
 		BranchesPanel: panelThree, CommitsPanel: panelFour, StashPanel: panelFive, SecondaryPanel: panelSix,
 	}
+	switch m.mainView {
+	case mainViewStaging:
+		titles[MainPanel] = m.stagingTitle()
+	case mainViewConflicts:
+		titles[MainPanel] = m.conflictTitle()
+	case mainViewOutput:
+		titles[MainPanel] = m.outputTitle()
+	case mainViewBlame:
+		titles[MainPanel] = m.blameTitle()
+	}
+	titles[CommitsPanel] = m.commitsTitle()
+	for _, panel := range leftpanels {
+		if tab := m.panels[panel].tab; tab != defaultTab {
+			titles[panel] = tabTitles[tab]
+		}
+	}
 
 	leftColumn := m.renderPanelColumn(leftpanels, titles, leftSectionWidth)
 	rightColumn := m.renderPanelColumn(rightpanels, titles, rightSectionWidth)
@@ -161,13 +180,39 @@ func (m Model) renderPanel(title string, width, height int, panel Panel) string
 		var builder strings.Builder
 		for i, line := range p.lines {
 			lineID := fmt.Sprintf("%s-line-%d", panel.ID(), i)
+			if panel == CommitsPanel && !m.rebaseTodo.active {
+				line = m.decorateCommitLine(line)
+			}
 			var finalLine string
 
-			if i == p.cursor && isFocused {
+			var commit *git.CommitLog
+			var graph graphRow
+			if panel == CommitsPanel {
+				commit, graph = m.commitLogLine(i)
+			}
+
+			if commit != nil {
+				// The line is rendered from the commit, keeping its colors
+				// on the selected line. The subject may have markers.
+				subject := commit.Subject
+				if parts := strings.SplitN(line, "\t", 4); len(parts) == 4 {
+					subject = parts[3]
+				}
+				base := lipgloss.NewStyle()
+				if i == p.cursor && isFocused {
+					base = m.theme.SelectedLine
+				}
+				finalLine = m.renderCommitLogLine(commit, graph, subject, contentWidth, base)
+				if i == p.cursor && isFocused {
+					padding := strings.Repeat(" ", max(contentWidth-lipgloss.Width(finalLine), 0))
+					finalLine += lipgloss.NewStyle().Inherit(base).Render(padding)
+				}
+				finalLine = lipgloss.NewStyle().MaxWidth(contentWidth).Render(finalLine)
+			} else if i == p.cursor && isFocused {
 				var cleanLine string
 				// For the selected line, strip any existing ANSI codes before applying selection style.
-				if panel == FilesPanel {
-					// For files panel, don't show the hidden path in the selection.
+				if panel == FilesPanel && p.tab == defaultTab {
+					// For files panel, join the tab-delimited columns.
 					parts := strings.Split(line, "\t")
 					if len(parts) >= 3 {
 						cleanLine = fmt.Sprintf("%s %s %s", parts[0], parts[1], parts[2])
@@ -334,8 +379,8 @@ func styleUnselectedLine(line string, panel Panel, theme Theme) string {
 		}
 		return fmt.Sprintf("%s %s %s", prefix, styledStatus, path)
 	case BranchesPanel:
-		parts := strings.SplitN(line, "\t", 2)
-		if len(parts) != 2 {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 2 {
 			return line
 		}
 		date, name := parts[0], parts[1]
@@ -344,17 +389,25 @@ func styleUnselectedLine(line string, panel Panel, theme Theme) string {
 		if strings.Contains(name, "(*)") {
 			styledName = theme.BranchCurrent.Render(name)
 		}
-		return lipgloss.JoinHorizontal(lipgloss.Left, styledDate, " ", styledName)
+		styled := lipgloss.JoinHorizontal(lipgloss.Left, styledDate, " ", styledName)
+		if len(parts) == 3 && parts[2] != "" {
+			styled = lipgloss.JoinHorizontal(lipgloss.Left, styled, " ", theme.BranchTracking.Render(parts[2]))
+		}
+		return styled
 	case CommitsPanel:
 		parts := strings.SplitN(line, "\t", 4)
+		if len(parts) == 3 {
+			// An entry of the Reflog tab.
+			selector, sha, subject := parts[0], parts[1], parts[2]
+			return lipgloss.JoinHorizontal(lipgloss.Left, theme.BranchDate.Render(selector), " ", theme.CommitSHA.Render(sha), " ", subject)
+		}
 		if len(parts) != 4 {
-			// This is a graph-only line, already colored by git.
-			// We just replace the placeholder node with a styled one.
-			return strings.ReplaceAll(line, "○", theme.GraphNode.Render("○"))
+			return line
 		}
 		graph, sha, author, subject := parts[0], parts[1], parts[2], parts[3]
 
-		// The graph string is already colored by git, but we style the node.
+		// The commit log is rendered from its commits, so this is a file
+		// history, whose graph is a placeholder node.
 		styledGraph := strings.ReplaceAll(graph, "○", theme.GraphNode.Render("○"))
 
 		// Apply our theme's styles to the other parts.
@@ -379,6 +432,27 @@ func styleUnselectedLine(line string, panel Panel, theme Theme) string {
 	return line
 }
 
+// formatTracking returns the indicator of how a branch relates to its
+// upstream, e.g. "↑2 ↓1". It is empty if the branch has no upstream.
+func formatTracking(t git.Tracking) string {
+	switch {
+	case t.Upstream == "":
+		return ""
+	case t.Gone:
+		return "upstream gone"
+	case t.Ahead == 0 && t.Behind == 0:
+		return "✓"
+	}
+	var counts []string
+	if t.Ahead > 0 {
+		counts = append(counts, fmt.Sprintf("↑%d", t.Ahead))
+	}
+	if t.Behind > 0 {
+		counts = append(counts, fmt.Sprintf("↓%d", t.Behind))
+	}
+	return strings.Join(counts, " ")
+}
+
 // styleStatus takes a 2-character git status code and returns a styled string.
 func styleStatus(status string, theme Theme) string {
 	if len(status) < 2 {
@@ -0,0 +1,310 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// workspaceConfig is the [workspace] section of config.toml, which lists the
+// repositories offered by the repository switcher.
+type workspaceConfig struct {
+	Repos    []string `toml:"repos"`     // Paths of repositories.
+	ScanDirs []string `toml:"scan_dirs"` // Directories whose subdirectories are listed if they are repositories.
+}
+
+// repoSwitcherState holds the state of the repository switcher pop-up.
+type repoSwitcherState struct {
+	repos  []string
+	cursor int
+	err    error // The error of the last attempt to open a repository.
+}
+
+// repoOpenedMsg is sent when a repository has been chosen, with the root of
+// its work tree, or the error if it cannot be opened.
+type repoOpenedMsg struct {
+	path    string
+	name    string
+	branch  string
+	parents []string // The repositories to return to, if it was opened as a submodule of one.
+	err     error
+	// confirmed is set once the user agreed to cancel the commands still
+	// running in the open repository.
+	confirmed bool
+}
+
+// repoPathRequestedMsg is sent when the path of a repository to open has been
+// entered.
+type repoPathRequestedMsg struct {
+	path string
+}
+
+// expandHome replaces a leading "~" in path with the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// isRepoDir reports whether dir is the root of a git work tree.
+func isRepoDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// workspaceRepos returns the repositories to offer in the repository switcher:
+// the recently opened ones first, then the configured ones, then the ones
+// found in the scanned directories.
+func workspaceRepos(workspace workspaceConfig, recent []string) []string {
+	var repos []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = expandHome(path)
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if path != "" && !seen[path] {
+			seen[path] = true
+			repos = append(repos, path)
+		}
+	}
+
+	for _, path := range recent {
+		add(path)
+	}
+	for _, path := range workspace.Repos {
+		add(path)
+	}
+	for _, dir := range workspace.ScanDirs {
+		dir = expandHome(dir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			log.Printf("failed to scan %s for repositories: %v", dir, err)
+			continue
+		}
+		for _, entry := range entries {
+			if path := filepath.Join(dir, entry.Name()); entry.IsDir() && isRepoDir(path) {
+				add(path)
+			}
+		}
+	}
+	return repos
+}
+
+// loadRecentRepos returns the recently opened repositories, most recent first.
+func loadRecentRepos() []string {
+	content, err := os.ReadFile(RecentReposFilePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed to read recent repositories: %v", err)
+		}
+		return nil
+	}
+	var repos []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			repos = append(repos, line)
+		}
+	}
+	return repos
+}
+
+// addRecentRepo moves path to the top of the recently opened repositories.
+func addRecentRepo(path string) error {
+	repos := []string{path}
+	for _, repo := range loadRecentRepos() {
+		if repo != path && len(repos) < maxRecentRepos {
+			repos = append(repos, repo)
+		}
+	}
+	content := strings.Join(repos, "\n") + "\n"
+	if err := os.WriteFile(RecentReposFilePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to save recent repositories: %w", err)
+	}
+	return nil
+}
+
+// rememberRepo returns a command that adds the open repository to the
+// recently opened ones.
+func (m Model) rememberRepo() tea.Cmd {
+	path := m.repoRoot
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := addRecentRepo(path); err != nil {
+			log.Printf("%v", err)
+		}
+		return nil
+	}
+}
+
+// showRepoSwitcher opens the repository switcher pop-up, with the error of the
+// last attempt to open a repository, if any.
+func (m *Model) showRepoSwitcher(err error) {
+	repos := workspaceRepos(m.workspace, loadRecentRepos())
+	cursor := 0
+	for i, repo := range repos {
+		if repo == m.repoRoot {
+			cursor = i
+			break
+		}
+	}
+	m.repoSwitcher = repoSwitcherState{repos: repos, cursor: cursor, err: err}
+	m.mode = modeRepoSwitcher
+}
+
+// openRepo returns a command that checks that path is inside a repository and
+// sends a repoOpenedMsg with the root of its work tree.
+func openRepo(path string) tea.Cmd {
+	return openNestedRepo(path, nil)
+}
+
+// openNestedRepo is like openRepo, but escape returns from the repository to
+// the last of parents.
+func openNestedRepo(path string, parents []string) tea.Cmd {
+	return func() tea.Msg {
+		root, err := git.NewGitCommands().WithRepoPath(expandHome(path)).GetRepoRoot()
+		if err != nil {
+			return repoOpenedMsg{path: path, err: fmt.Errorf("cannot open %s: %w", path, err)}
+		}
+		name, branch, _ := git.NewGitCommands().WithRepoPath(root).GetRepoInfo()
+		return repoOpenedMsg{path: root, name: name, branch: branch, parents: parents}
+	}
+}
+
+// promptRepoPath asks for the path of a repository to open.
+func (m *Model) promptRepoPath() tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = "Open Repository at Path"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		return func() tea.Msg { return repoPathRequestedMsg{path: input} }
+	}
+	return nil
+}
+
+// handleRepoOpenedMsg switches to a freshly opened repository, or shows the
+// repository switcher with the error if it cannot be opened.
+func (m Model) handleRepoOpenedMsg(msg repoOpenedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.showRepoSwitcher(msg.err)
+		return m, nil
+	}
+	if running := m.runningCommand(); running != "" && !msg.confirmed {
+		m.mode = modeConfirm
+		m.confirmMessage = fmt.Sprintf("%s is still running. Cancel it and open %s?", running, msg.path)
+		m.confirmCallback = func(confirmed bool) tea.Cmd {
+			if !confirmed {
+				return nil
+			}
+			msg.confirmed = true
+			return func() tea.Msg { return msg }
+		}
+		return m, nil
+	}
+	if m.cancel != nil {
+		m.cancel() // Kill the git commands still running in the previous repository.
+	}
+
+	next := newModel(m.git.WithRepoPath(msg.path), msg.path, msg.name, msg.branch)
+	next.width, next.height = m.width, m.height
+	next.theme, next.themeNames, next.themeIndex = m.theme, m.themeNames, m.themeIndex
+	next.help.Width = m.help.Width
+	next.helpViewport = m.helpViewport
+	next.keymap = m.keymap
+	next.workspace = m.workspace
+	next.CommandHistory = m.CommandHistory
+	next.watchRepo = m.watchRepo
+	next.parentRepos = msg.parents
+	next = next.recalculateLayout()
+
+	watch := next.watchRepo
+	return next, tea.Batch(next.Init(), func() tea.Msg {
+		if watch != nil {
+			watch(msg.path)
+		}
+		return nil
+	})
+}
+
+// updateRepoSwitcher handles updates while the repository switcher is shown.
+func (m Model) updateRepoSwitcher(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return m.handleWindowSizeMsg(msg)
+	case tea.KeyMsg:
+		s := &m.repoSwitcher
+		switch {
+		case Matches(msg, m.keymap["up"]):
+			if s.cursor > 0 {
+				s.cursor--
+			}
+		case Matches(msg, m.keymap["down"]):
+			if s.cursor < len(s.repos)-1 {
+				s.cursor++
+			}
+		case msg.Type == tea.KeyEnter:
+			if s.cursor < len(s.repos) {
+				return m, openRepo(s.repos[s.cursor])
+			}
+		case Matches(msg, m.keymap["open_repo_path"]):
+			return m, m.promptRepoPath()
+		case Matches(msg, m.keymap["escape"]):
+			if m.repoRoot == "" {
+				return m, tea.Quit // There is no repository to go back to.
+			}
+			m.mode = modeNormal
+		case Matches(msg, m.keymap["quit"]):
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// renderRepoSwitcherPopup creates the view for the repository switcher pop-up.
+func (m Model) renderRepoSwitcherPopup() string {
+	s := m.repoSwitcher
+	lines := []string{m.theme.ActiveTitle.Render(" Switch Repository ")}
+	if m.repoRoot == "" {
+		lines = append(lines, "Not in a git repository. Choose one to open:")
+	}
+	if len(s.repos) == 0 {
+		lines = append(lines, m.theme.InactiveTitle.Render(
+			"No repositories yet. Add them to [workspace] in "+ConfigFilePath+"."))
+	}
+	for i, repo := range s.repos {
+		line := fmt.Sprintf("%s  %s", filepath.Base(repo), m.theme.InactiveTitle.Render(repo))
+		if repo == m.repoRoot {
+			line = "(*) → " + line
+		}
+		if i == s.cursor {
+			line = m.theme.SelectedLine.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if s.err != nil {
+		lines = append(lines, m.theme.ErrorText.Render(s.err.Error()))
+	}
+	lines = append(lines, m.theme.InactiveTitle.Render(
+		fmt.Sprintf(" (Enter to open, %s to enter a path, Esc to cancel) ", m.keymap.binding("open_repo_path").Help().Key)))
+
+	return lipgloss.NewStyle().
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.ActiveBorder.Style.GetForeground()).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gitxtui/gitx/internal/git"
+)
+
+// worktreesUpdatedMsg is sent when the worktrees have been fetched.
+type worktreesUpdatedMsg struct {
+	worktrees []*git.Worktree
+}
+
+// worktreePathMsg is sent when the path of a new worktree has been entered.
+type worktreePathMsg struct {
+	path string
+}
+
+// handleWorktreesUpdatedMsg renders the worktrees into the Worktrees tab of the
+// Files panel.
+func (m Model) handleWorktreesUpdatedMsg(msg worktreesUpdatedMsg) (Model, tea.Cmd) {
+	if m.panels[FilesPanel].tab != worktreesTab {
+		return m, nil
+	}
+
+	lines := make([]string, len(msg.worktrees))
+	for i, wt := range msg.worktrees {
+		name := filepath.Base(wt.Path)
+		if wt.Path == m.repoRoot {
+			name = "(*) → " + name
+		}
+		checkout := wt.Branch
+		switch {
+		case wt.Bare:
+			checkout = "(bare)"
+		case wt.Detached:
+			checkout = shortSHA(wt.Head)
+		}
+		line := fmt.Sprintf("%s %s", m.theme.BranchCurrent.Render(name), m.theme.CommitSHA.Render(checkout))
+		if wt.Main {
+			line += " " + m.theme.BranchDate.Render("main")
+		}
+		if wt.Locked {
+			line += " " + m.theme.BranchDate.Render("locked")
+		}
+		if wt.Prunable {
+			line += " " + m.theme.ErrorText.Render("prunable")
+		}
+		lines[i] = line
+	}
+	if len(lines) == 0 {
+		lines = []string{"No worktrees."}
+	}
+
+	m.worktrees = msg.worktrees
+	content := strings.Join(lines, "\n")
+	m.panels[FilesPanel].lines = lines
+	m.panels[FilesPanel].content = content
+	m.panels[FilesPanel].viewport.SetContent(content)
+	if m.panels[FilesPanel].cursor >= len(lines) {
+		m.panels[FilesPanel].cursor = len(lines) - 1
+	}
+	return m, m.updateMainPanel()
+}
+
+// selectedWorktree returns the worktree under the cursor of the Worktrees tab,
+// or nil if there is none.
+func (m Model) selectedWorktree() *git.Worktree {
+	cursor := m.panels[FilesPanel].cursor
+	if m.panels[FilesPanel].tab != worktreesTab || cursor < 0 || cursor >= len(m.worktrees) {
+		return nil
+	}
+	return m.worktrees[cursor]
+}
+
+// worktreeDetails describes the selected worktree for the Main panel.
+func (m Model) worktreeDetails() (string, error) {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return "", nil
+	}
+	details := fmt.Sprintf("Worktree: %s\nPath: %s\n", m.theme.BranchCurrent.Render(filepath.Base(wt.Path)), wt.Path)
+	if wt.Branch != "" {
+		details += fmt.Sprintf("Branch: %s\n", wt.Branch)
+	}
+	if wt.Locked {
+		details += "Locked"
+		if wt.LockReason != "" {
+			details += ": " + wt.LockReason
+		}
+		details += "\n"
+	}
+	if wt.Prunable {
+		details += fmt.Sprintf("Prunable: %s\n", wt.PrunableReason)
+	}
+	if wt.Head == "" {
+		return details, nil
+	}
+	log, err := m.git.ShowLog(git.LogOptions{Graph: true, Color: "always", Branch: wt.Head})
+	if err != nil {
+		return details, err
+	}
+	return details + "\n" + log, nil
+}
+
+// handleWorktreesKeys handles the keybindings of the Worktrees tab of the Files
+// panel.
+func (m *Model) handleWorktreesKeys(msg tea.KeyMsg) tea.Cmd {
+	if handled, cmd := m.handleCursorMovement(msg); handled {
+		return cmd
+	}
+	switch {
+	case Matches(msg, m.keymap["new_worktree"]):
+		return m.promptNewWorktree()
+	case Matches(msg, m.keymap["prune_worktrees"]):
+		return func() tea.Msg {
+			_, cmdStr, err := m.git.PruneWorktrees()
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	path := wt.Path
+
+	switch {
+	case Matches(msg, m.keymap["checkout"]):
+		if path == m.repoRoot {
+			return nil
+		}
+		if wt.Bare || wt.Prunable {
+			return func() tea.Msg { return errMsg{fmt.Errorf("worktree %s has no work tree to open", path)} }
+		}
+		return openNestedRepo(path, m.parentRepos) // Stay in the submodule session, if any.
+
+	case Matches(msg, m.keymap["remove_worktree"]):
+		if wt.Main {
+			return func() tea.Msg { return errMsg{fmt.Errorf("the main worktree %s cannot be removed", path)} }
+		}
+		if path == m.repoRoot {
+			return func() tea.Msg { return errMsg{fmt.Errorf("switch to another worktree before removing %s", path)} }
+		}
+		m.mode = modeConfirm
+		m.confirmMessage = fmt.Sprintf("Remove worktree %s?", path)
+		m.confirmCallback = func(confirmed bool) tea.Cmd {
+			m.mode = modeNormal
+			if !confirmed {
+				return nil
+			}
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.RemoveWorktree(path, false)
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+
+	case Matches(msg, m.keymap["lock_worktree"]):
+		if wt.Main {
+			return func() tea.Msg { return errMsg{fmt.Errorf("the main worktree %s cannot be locked", path)} }
+		}
+		if wt.Locked {
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.UnlockWorktree(path)
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+		m.mode = modeInput
+		m.promptTitle = fmt.Sprintf("Lock Worktree %s (reason, optional)", filepath.Base(path))
+		m.textInput.SetValue("")
+		m.textInput.Focus()
+		m.inputCallback = func(input string) tea.Cmd {
+			m.mode = modeNormal
+			return func() tea.Msg {
+				_, cmdStr, err := m.git.LockWorktree(path, input)
+				if err != nil {
+					return errMsg{err}
+				}
+				return commandExecutedMsg{cmdStr}
+			}
+		}
+	}
+	return nil
+}
+
+// promptNewWorktree asks for the path of a new worktree, then for the branch
+// to check out in it.
+func (m *Model) promptNewWorktree() tea.Cmd {
+	m.mode = modeInput
+	m.promptTitle = "New Worktree Path"
+	suggestion := ""
+	if m.repoRoot != "" {
+		suggestion = filepath.Join(filepath.Dir(m.repoRoot), filepath.Base(m.repoRoot)+"-")
+	}
+	m.textInput.SetValue(suggestion)
+	m.textInput.Focus()
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg { return worktreePathMsg{path: input} }
+	}
+	return nil
+}
+
+// handleWorktreePathMsg asks for the branch to check out in the new worktree
+// and adds it. A branch that does not exist yet is created from HEAD.
+func (m Model) handleWorktreePathMsg(msg worktreePathMsg) (Model, tea.Cmd) {
+	m.mode = modeInput
+	m.promptTitle = fmt.Sprintf("Branch for Worktree %s (new or existing)", filepath.Base(msg.path))
+	m.textInput.SetValue(filepath.Base(msg.path))
+	m.textInput.Focus()
+	gc := m.git
+	m.inputCallback = func(input string) tea.Cmd {
+		if input == "" {
+			return nil
+		}
+		return func() tea.Msg {
+			branches, err := gc.GetBranches()
+			if err != nil {
+				return errMsg{err}
+			}
+			exists := false
+			for _, b := range branches {
+				if b.Name == input {
+					exists = true
+					break
+				}
+			}
+			_, cmdStr, err := gc.AddWorktree(git.WorktreeOptions{Path: msg.path, Branch: input, NewBranch: !exists})
+			if err != nil {
+				return errMsg{err}
+			}
+			return commandExecutedMsg{cmdStr}
+		}
+	}
+	return m, nil
+}
+
+// formatBranchWorktree returns the marker of a branch that is checked out in
+// another worktree, or an empty string.
+func formatBranchWorktree(b *git.Branch) string {
+	if b.Worktree == "" {
+		return ""
+	}
+	return "⎇ " + filepath.Base(b.Worktree)
+}